@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"arbitrage-bot/internal/config"
+)
+
+// runConfig handles the config subcommands.
+//
+//	arbitrage-bot config validate [-dir config]
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: arbitrage-bot config validate [-dir config]")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+	dir := fs.String("dir", "config", "directory containing config.yaml")
+	fs.Parse(args[1:])
+
+	if _, err := config.LoadConfig(*dir); err != nil {
+		var verr *config.ValidationError
+		if errors.As(err, &verr) {
+			fmt.Fprintf(os.Stderr, "config is invalid, %d problems:\n", len(verr.Problems))
+			for _, p := range verr.Problems {
+				fmt.Fprintf(os.Stderr, "  - %s\n", p)
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("config OK")
+}
@@ -2,51 +2,370 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
 
+	"arbitrage-bot/internal/api"
 	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/events"
 	"arbitrage-bot/internal/exchange"
 	"arbitrage-bot/internal/exchange/edgex"
 	"arbitrage-bot/internal/exchange/hyperliquid"
 	"arbitrage-bot/internal/exchange/lighter"
+	"arbitrage-bot/internal/ledger"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
+	"arbitrage-bot/internal/metrics"
+	"arbitrage-bot/internal/notify"
+	"arbitrage-bot/internal/orderbook"
+	"arbitrage-bot/internal/state"
 	"arbitrage-bot/internal/strategy"
 )
 
+var log = logger.For("main")
+
+// fatal logs at error level and exits
+func fatal(msg string, args ...any) {
+	log.Error(msg, args...)
+	os.Exit(1)
+}
+
 func main() {
+	// Validating the config must not depend on the config being valid
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfig(os.Args[2:])
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig("config")
+	if verr := (*config.ValidationError)(nil); errors.As(err, &verr) {
+		fatal("invalid config, run `config validate` for details", "problems", verr.Problems)
+	} else if err != nil {
+		fatal("failed to load config", logger.Err(err))
+	}
+
+	if err := logger.Init(cfg.App.LogFormat, cfg.App.LogLevel, cfg.App.LogLevels); err != nil {
+		fatal("invalid logging config", logger.Err(err))
+	}
+
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "pnl":
+			runPnL(cfg, os.Args[2:])
+		default:
+			fatal("unknown command (available: pnl, config)", "command", os.Args[1])
+		}
+		return
+	}
+
+	log.Info("config loaded",
+		"port", cfg.App.Port,
+		"funding_arb_enabled", cfg.Strategies.FundingArb.Enabled,
+		"hyperliquid_wallet", cfg.Exchanges.Hyperliquid.WalletAddress)
+	// Secrets print as [REDACTED]
+	log.Debug("effective config", "config", *cfg)
+
+	// Cancelled on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	notifier, err := notify.NewRouterFromConfig(cfg.Notifications)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("invalid notifications config", logger.Err(err))
 	}
+	// Runs past ctx so alerts raised during shutdown still go out
+	notifyCtx, stopNotify := context.WithCancel(context.Background())
+	notifyDone := make(chan struct{})
+	go func() {
+		defer close(notifyDone)
+		notifier.Start(notifyCtx)
+	}()
 
-	log.Printf("Config loaded successfully")
-	log.Printf("App Port: %d", cfg.App.Port)
-	log.Printf("Funding Arb Enabled: %v", cfg.Strategies.FundingArb.Enabled)
-	log.Printf("Hyperliquid Wallet: %s", cfg.Exchanges.Hyperliquid.WalletAddress)
+	// Market, order and venue events, published by the feeds and adapters
+	bus := events.NewBus()
+	go logAccountEvents(ctx, bus)
+
+	// Market data pushed over WebSocket, read by the adapters while fresh
+	var market *marketdata.Cache
+	var books *orderbook.Aggregator
+	var feeds []marketFeed
+	if cfg.App.MarketDataMaxAgeMs > 0 {
+		maxAge := time.Duration(cfg.App.MarketDataMaxAgeMs) * time.Millisecond
+		market = marketdata.NewCache(maxAge)
+		// Cross-venue view of the books the feeds publish
+		books = orderbook.NewAggregator(maxAge, cfg.App.BookUnits)
+		go books.Run(ctx, bus)
+		feeds = startFeeds(ctx, cfg, market, bus)
+	}
 
 	// Initialize Exchanges
-	exchanges := make(map[string]exchange.Exchange)
-	exchanges["hyperliquid"] = hyperliquid.NewClient(cfg.Exchanges.Hyperliquid)
-	exchanges["lighter"] = lighter.NewClient(cfg.Exchanges.Lighter)
-	exchanges["edgex"] = edgex.NewClient(cfg.Exchanges.EdgeX)
+	venues := newRegistry(cfg, market, bus, func(name string, st exchange.VenueStatus) {
+		if st.Ready {
+			metrics.VenueReady.WithLabelValues(name).Set(1)
+			notifier.Info("Venue ready", name, map[string]any{"venue": name})
+		} else {
+			metrics.VenueReady.WithLabelValues(name).Set(0)
+			notifier.Warn("Venue not ready", st.Error, map[string]any{"venue": name})
+		}
+	})
+	exchanges := venues.All()
+
+	ldg, err := ledger.Open(journalPath(cfg))
+	if err != nil {
+		fatal("failed to open PnL journal", logger.Err(err))
+	}
 
 	// Initialize and Start Strategy
+	var strategies []strategy.Strategy
+	var attributor ledger.Attributor
+	var arbStrategy *strategy.FundingArbStrategy
+	var xpStrategy *strategy.XPFarmingStrategy
 	if cfg.Strategies.FundingArb.Enabled {
-		arbStrategy := strategy.NewFundingArbStrategy(cfg.Strategies.FundingArb, exchanges)
-
-		// Run in background
-		ctx := context.Background()
-		go arbStrategy.Start(ctx)
+		store := state.NewStore(filepath.Join(cfg.App.DataDir, "funding_arb_state.json"))
+		arbStrategy = strategy.NewFundingArbStrategy(cfg.Strategies.FundingArb, venues, store, ldg, notifier, bus)
+		strategies = append(strategies, arbStrategy)
+		attributor = arbStrategy
 	}
 
 	if cfg.Strategies.XPFarming.Enabled {
-		xpStrategy := strategy.NewXPFarmingStrategy(cfg.Strategies.XPFarming, exchanges)
+		xpStrategy = strategy.NewXPFarmingStrategy(cfg.Strategies.XPFarming, venues, notifier)
+		strategies = append(strategies, xpStrategy)
+	}
+
+	// Apply edits to the strategies section without a restart. Strategies
+	// that weren't enabled at startup can only be paused/resumed, not created.
+	config.WatchStrategies(cfg, func(old, new config.StrategiesConfig) {
+		for _, feed := range feeds {
+			feed.Track(new.FundingArb.Pairs)
+		}
+		if arbStrategy != nil {
+			arbStrategy.UpdateConfig(new.FundingArb)
+		} else if new.FundingArb.Enabled {
+			log.Warn("funding_arb enabled in config, restart to start it")
+		}
+		if xpStrategy != nil {
+			xpStrategy.UpdateConfig(new.XPFarming)
+		} else if new.XPFarming.Enabled {
+			log.Warn("xp_farming enabled in config, restart to start it")
+		}
+	})
+
+	// Run in background
+	var wg sync.WaitGroup
+	for _, st := range strategies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			st.Start(ctx)
+		}()
+	}
+
+	// Ingest actual funding payments into the journal
+	if cfg.App.FundingPollIntervalMs > 0 {
+		interval := time.Duration(cfg.App.FundingPollIntervalMs) * time.Millisecond
+		collector := ledger.NewFundingCollector(ldg, exchanges, interval, attributor)
+		go collector.Start(ctx)
+	}
+
+	// Take venues that fail their check out of rotation until they recover
+	if cfg.App.VenueCheckIntervalMs > 0 {
+		interval := time.Duration(cfg.App.VenueCheckIntervalMs) * time.Millisecond
+		go venues.Watch(ctx, interval)
+	}
+
+	// Keep balance and position gauges fresh
+	if cfg.App.MetricsPollIntervalMs > 0 {
+		interval := time.Duration(cfg.App.MetricsPollIntervalMs) * time.Millisecond
+		go metrics.PollAccounts(ctx, exchanges, interval)
+	}
+
+	// Prometheus scrapes its own listener, with or without the API
+	if cfg.App.MetricsPort > 0 {
+		go metrics.Serve(ctx, cfg.App.MetricsPort)
+	}
+
+	// Status and control API
+	if cfg.App.APIToken != "" {
+		server := api.NewServer(cfg.App.Port, string(cfg.App.APIToken), venues, strategies, ldg, books)
+		go server.Start(ctx)
+	} else {
+		log.Warn("app.api_token not set - HTTP API disabled")
+	}
+
+	<-ctx.Done()
+	stop() // a second signal kills the process immediately
+	log.Info("shutdown signal received, stopping")
+	shutdown(cfg, &wg, strategies, ldg)
+
+	stopNotify()
+	select {
+	case <-notifyDone:
+	case <-time.After(notifyFlushTimeout):
+		log.Warn("pending notifications not sent before exit")
+	}
+}
+
+// How long to wait for queued notifications on exit
+const notifyFlushTimeout = 5 * time.Second
+
+// Used when app.shutdown_timeout_ms is not set
+const defaultShutdownTimeout = 15 * time.Second
+
+// Account events the log subscriber buffers before the bus drops them
+const accountEventBuffer = 256
+
+// shutdown waits for strategies to finish their current tick, optionally
+// cancels their resting orders and flushes the journal. If that takes longer
+// than the configured deadline the process exits anyway.
+func shutdown(cfg *config.Config, wg *sync.WaitGroup, strategies []strategy.Strategy, ldg *ledger.Ledger) {
+	timeout := time.Duration(cfg.App.ShutdownTimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		wg.Wait()
 
-		// Run in background
-		ctx := context.Background()
-		go xpStrategy.Start(ctx)
+		if cfg.App.CancelOrdersOnExit {
+			for _, st := range strategies {
+				st.CancelRestingOrders()
+			}
+		}
+
+		if err := ldg.Close(); err != nil {
+			log.Error("failed to flush PnL journal", logger.Err(err))
+		}
+	}()
+
+	select {
+	case <-done:
+		log.Info("shutdown complete")
+	case <-time.After(timeout):
+		fatal("shutdown did not finish in time, exiting", "timeout", timeout)
+	}
+}
+
+// marketFeed streams an exchange's market data into the cache.
+type marketFeed interface {
+	Track(symbols []string)
+	Start(ctx context.Context)
+}
+
+// startFeeds starts the WebSocket feeds of the enabled exchanges that have
+// a ws_url, streaming the funding_arb pairs.
+func startFeeds(ctx context.Context, cfg *config.Config, market *marketdata.Cache, bus *events.Bus) []marketFeed {
+	var feeds []marketFeed
+	ex := cfg.Exchanges
+	if ex.Hyperliquid.Enabled && ex.Hyperliquid.WSURL != "" {
+		feed := hyperliquid.NewFeed(ex.Hyperliquid, market, bus)
+		for account, c := range ex.Hyperliquid.AccountConfigs() {
+			feed.TrackAccount(config.VenueName("hyperliquid", account), c)
+		}
+		feeds = append(feeds, feed)
+	}
+	if ex.Lighter.Enabled && ex.Lighter.WSURL != "" {
+		feed := lighter.NewFeed(ex.Lighter, market, bus)
+		for account, c := range ex.Lighter.AccountConfigs() {
+			feed.TrackAccount(config.VenueName("lighter", account), c)
+		}
+		feeds = append(feeds, feed)
+	}
+	if ex.EdgeX.Enabled && ex.EdgeX.WSURL != "" {
+		feeds = append(feeds, edgex.NewFeed(ex.EdgeX, market, bus))
 	}
 
-	// Keep main alive
-	select {}
+	for _, feed := range feeds {
+		feed.Track(cfg.Strategies.FundingArb.Pairs)
+		go feed.Start(ctx)
+	}
+	return feeds
+}
+
+// logAccountEvents logs the account events published on bus.
+func logAccountEvents(ctx context.Context, bus *events.Bus) {
+	sub := bus.Subscribe("log", accountEventBuffer, events.KindFill, events.KindOrder, events.KindPosition)
+	defer bus.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-sub.Events():
+			switch e := e.(type) {
+			case events.Fill:
+				log.Info("order filled", logger.KeyVenue, e.Venue, logger.KeySymbol, e.Symbol, logger.KeyOrderID, e.OrderID,
+					"side", e.Side, "size", e.Size, "price", e.Price, "fee", e.Fee)
+			case events.OrderUpdate:
+				log.Info("order updated", logger.KeyVenue, e.Venue, logger.KeySymbol, e.Symbol, logger.KeyOrderID, e.OrderID,
+					"status", e.Status, "remaining", e.Size)
+			case events.PositionUpdate:
+				log.Debug("position updated", logger.KeyVenue, e.Venue, logger.KeySymbol, e.Position.Symbol, "size", e.Position.Size)
+			}
+		}
+	}
+}
+
+// newRegistry builds the enabled exchanges, publishing their order results
+// and status changes on bus. market, bus and onStatus may be nil.
+func newRegistry(cfg *config.Config, market *marketdata.Cache, bus *events.Bus, onStatus func(name string, st exchange.VenueStatus)) *exchange.Registry {
+	r := exchange.NewRegistry(func(name string, exc exchange.Exchange) exchange.Exchange {
+		return events.Publish(bus, name, metrics.Instrument(name, exc))
+	})
+	publishStatus := func(name string) {
+		bus.Publish(events.VenueStatus{Venue: name, Status: r.Status()[name]})
+	}
+	r.OnStatusChange = func(name string, st exchange.VenueStatus) {
+		if onStatus != nil {
+			onStatus(name, st)
+		}
+		publishStatus(name)
+	}
+	r.BreakerFailures = cfg.App.BreakerFailures
+	r.BreakerCooldown = time.Duration(cfg.App.BreakerCooldownMs) * time.Millisecond
+	r.OnBreakerChange = func(name string, state exchange.BreakerState) {
+		metrics.VenueBreakerState.WithLabelValues(name).Set(float64(state))
+		// Status takes the breaker's lock, which is held while this runs
+		go publishStatus(name)
+	}
+
+	// One venue per account: "hyperliquid" for the default account,
+	// "hyperliquid:<name>" for named ones
+	// Accounts of one exchange share its rate limit, which venues count per IP.
+	ex := cfg.Exchanges
+	hlLimiter := hyperliquid.NewLimiter(ex.Hyperliquid)
+	for account, c := range ex.Hyperliquid.AccountConfigs() {
+		r.Register(config.VenueName("hyperliquid", account), ex.Hyperliquid.Enabled, func() exchange.Exchange {
+			return hyperliquid.NewClient(c, hlLimiter, market)
+		})
+	}
+	lighterLimiter := lighter.NewLimiter(ex.Lighter)
+	for account, c := range ex.Lighter.AccountConfigs() {
+		r.Register(config.VenueName("lighter", account), ex.Lighter.Enabled, func() exchange.Exchange {
+			return lighter.NewClient(c, lighterLimiter, market)
+		})
+	}
+	edgexLimiter := edgex.NewLimiter(ex.EdgeX)
+	for account, c := range ex.EdgeX.AccountConfigs() {
+		r.Register(config.VenueName("edgex", account), ex.EdgeX.Enabled, func() exchange.Exchange {
+			return edgex.NewClient(c, edgexLimiter, market)
+		})
+	}
+	for _, name := range r.Names() {
+		metrics.VenueBreakerState.WithLabelValues(name).Set(float64(exchange.BreakerClosed))
+	}
+	return r
+}
+
+func journalPath(cfg *config.Config) string {
+	return filepath.Join(cfg.App.DataDir, "journal.jsonl")
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/ledger"
+	"arbitrage-bot/internal/logger"
+)
+
+// runPnL prints daily PnL summaries, per-leg totals and expected vs.
+// received funding from the journal.
+//
+//	arbitrage-bot pnl [-days 7] [-mark]
+func runPnL(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("pnl", flag.ExitOnError)
+	days := fs.Int("days", 7, "number of days to summarize")
+	mark := fs.Bool("mark", false, "fetch live prices to mark open positions to market")
+	fs.Parse(args)
+
+	ldg, err := ledger.Open(journalPath(cfg))
+	if err != nil {
+		fatal("failed to open PnL journal", logger.Err(err))
+	}
+	defer ldg.Close()
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(*days - 1))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "DATE\tPAIR\tVENUE\tSYMBOL\tTRADING\tFEES\tFUNDING\tNET\t")
+	for _, d := range ldg.Daily(since) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.4f\t%.4f\t%.4f\t%.4f\t\n",
+			d.Date, d.PairID, d.Exchange, d.Symbol, d.TradingPnL, d.Fees, d.Funding, d.Net)
+	}
+	w.Flush()
+
+	var marks ledger.MarkFunc
+	if *mark {
+		exchanges := newRegistry(cfg, nil, nil, nil).All()
+		marks = func(exchangeName, symbol string) (float64, bool) {
+			exc, ok := exchanges[exchangeName]
+			if !ok {
+				return 0, false
+			}
+			price, err := exc.GetPrice(symbol)
+			if err != nil {
+				log.Warn("failed to get mark price", logger.KeyVenue, exchangeName, logger.KeySymbol, symbol, logger.Err(err))
+				return 0, false
+			}
+			return price, true
+		}
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PAIR\tVENUE\tSYMBOL\tSIZE\tENTRY\tMARK\tREALIZED\tUNREALIZED\tFEES\tFUNDING\tNET\t")
+	var total float64
+	for _, leg := range ldg.Legs(marks) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.4f\t%.4f\t%.4f\t%.4f\t%.4f\t%.4f\t%.4f\t%.4f\t\n",
+			leg.PairID, leg.Exchange, leg.Symbol, leg.Size, leg.AvgEntry, leg.Mark,
+			leg.TradingPnL, leg.Unrealized, leg.Fees, leg.Funding, leg.Net)
+		total += leg.Net
+	}
+	w.Flush()
+	fmt.Printf("\nTotal Net PnL: %.4f\n", total)
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PAIR\tVENUE\tSYMBOL\tPAYMENTS\tFUNDING RECEIVED\tEXPECTED\tDIFF\t")
+	for _, fc := range ldg.CompareFunding(since) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.4f\t%.4f\t%.4f\t\n",
+			fc.PairID, fc.Exchange, fc.Symbol, fc.Payments, fc.Actual, fc.Expected, fc.Diff)
+	}
+	w.Flush()
+}
@@ -3,17 +3,19 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"arbitrage-bot/internal/logger"
 	"arbitrage-bot/pkg/ws"
 )
 
+var log = logger.For("test_ws")
+
 func main() {
-	log.Println("Testing EdgeX WebSocket...")
+	log.Info("testing EdgeX WebSocket")
 
 	// Create WebSocket client
 	wsClient := ws.NewEdgeXWSClient("wss://quote.edgex.exchange/api/v1/public/ws")
@@ -21,7 +23,8 @@ func main() {
 	// Connect
 	ctx := context.Background()
 	if err := wsClient.Connect(ctx); err != nil {
-		log.Fatalf("Failed to connect: %v", err)
+		log.Error("failed to connect", logger.Err(err))
+		os.Exit(1)
 	}
 	defer wsClient.Close()
 
@@ -30,26 +33,26 @@ func main() {
 
 	// Subscribe to BTC ticker (contractId: 10000001)
 	err := wsClient.Subscribe("ticker.10000001", func(data json.RawMessage) {
-		log.Printf("Received BTC ticker data: %s", string(data))
+		log.Info("received BTC ticker", "data", string(data))
 	})
 	if err != nil {
-		log.Printf("Failed to subscribe: %v", err)
+		log.Error("failed to subscribe", logger.Err(err))
 	}
 
 	// Subscribe to ETH ticker (contractId: 10000002)
 	err = wsClient.Subscribe("ticker.10000002", func(data json.RawMessage) {
-		log.Printf("Received ETH ticker data: %s", string(data))
+		log.Info("received ETH ticker", "data", string(data))
 	})
 	if err != nil {
-		log.Printf("Failed to subscribe: %v", err)
+		log.Error("failed to subscribe", logger.Err(err))
 	}
 
-	log.Println("Subscribed to tickers. Press Ctrl+C to exit...")
+	log.Info("subscribed to tickers, press Ctrl+C to exit")
 
 	// Wait for interrupt signal
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	<-sigCh
 
-	log.Println("Shutting down...")
+	log.Info("shutting down")
 }
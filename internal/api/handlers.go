@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/orderbook"
+	"arbitrage-bot/internal/strategy"
+)
+
+type fundingRatesResponse struct {
+	Rates     map[string]map[string]strategy.Quote `json:"rates"` // pair -> exchange -> rate and when it was received
+	UpdatedAt time.Time                            `json:"updated_at"`
+}
+
+type venuePositions struct {
+	Positions []*exchange.Position `json:"positions"`
+	Error     string               `json:"error,omitempty"`
+}
+
+type venueOrders struct {
+	Orders []*exchange.Order `json:"orders"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// Levels of the consolidated book returned when the query doesn't say
+const defaultBookLevels = 20
+
+type bookResponse struct {
+	Symbol string          `json:"symbol"`
+	Depth  orderbook.Depth `json:"depth"`
+
+	// Set when the query asks for a size
+	Size    float64               `json:"size,omitempty"`
+	Buy     []orderbook.Execution `json:"buy,omitempty"`  // best venue first
+	Sell    []orderbook.Execution `json:"sell,omitempty"` // best venue first
+	Spreads []orderbook.Spread    `json:"spreads,omitempty"`
+}
+
+func (s *Server) handleFundingRates(w http.ResponseWriter, r *http.Request) {
+	if s.fundingArb == nil {
+		writeError(w, http.StatusNotFound, "funding_arb strategy is not enabled")
+		return
+	}
+
+	rates, updatedAt := s.fundingArb.Rates()
+	writeJSON(w, http.StatusOK, fundingRatesResponse{Rates: rates, UpdatedAt: updatedAt})
+}
+
+func (s *Server) handleOpportunities(w http.ResponseWriter, r *http.Request) {
+	if s.fundingArb == nil {
+		writeError(w, http.StatusNotFound, "funding_arb strategy is not enabled")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.fundingArb.Opportunities())
+}
+
+func (s *Server) handlePositions(w http.ResponseWriter, r *http.Request) {
+	result := make(map[string]venuePositions)
+	var mu sync.Mutex
+
+	s.forEachExchange(func(name string, exc exchange.Exchange) {
+		positions, err := exc.GetPositions()
+		vp := venuePositions{Positions: positions}
+		if err != nil {
+			vp.Error = err.Error()
+		}
+		mu.Lock()
+		result[name] = vp
+		mu.Unlock()
+	})
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	result := make(map[string]venueOrders)
+	var mu sync.Mutex
+
+	s.forEachExchange(func(name string, exc exchange.Exchange) {
+		orders, err := exc.GetOpenOrders()
+		vo := venueOrders{Orders: orders}
+		if err != nil {
+			vo.Error = err.Error()
+		}
+		mu.Lock()
+		result[name] = vo
+		mu.Unlock()
+	})
+	writeJSON(w, http.StatusOK, result)
+}
+
+// forEachExchange calls fn for every exchange concurrently and waits.
+func (s *Server) forEachExchange(fn func(name string, exc exchange.Exchange)) {
+	var wg sync.WaitGroup
+	for name, exc := range s.venues.All() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(name, exc)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Server) handleVenues(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.venues.Status())
+}
+
+// handleBook returns the consolidated book of a pair and, given ?size=, what
+// buying and selling that size costs on each venue and the effective spreads
+// between them. ?levels= limits the depth (default 20, 0 for all).
+func (s *Server) handleBook(w http.ResponseWriter, r *http.Request) {
+	if s.books == nil {
+		writeError(w, http.StatusNotFound, "order books need WebSocket market data (app.market_data_max_age_ms)")
+		return
+	}
+
+	levels := defaultBookLevels
+	if v := r.URL.Query().Get("levels"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "levels must be a non-negative integer")
+			return
+		}
+		levels = n
+	}
+	var size float64
+	if v := r.URL.Query().Get("size"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 {
+			writeError(w, http.StatusBadRequest, "size must be a positive number")
+			return
+		}
+		size = f
+	}
+
+	symbol := r.PathValue("symbol")
+	res := bookResponse{Symbol: symbol, Depth: s.books.Depth(symbol, levels)}
+	if size > 0 {
+		res.Size = size
+		res.Buy = s.books.Executions(symbol, "buy", size)
+		res.Sell = s.books.Executions(symbol, "sell", size)
+		res.Spreads = s.books.Spreads(symbol, size)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleDailyPnL(w http.ResponseWriter, r *http.Request) {
+	days := 7
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "days must be a positive integer")
+			return
+		}
+		days = n
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	writeJSON(w, http.StatusOK, s.ledger.Daily(since))
+}
+
+func (s *Server) handleStrategies(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(s.strategies))
+	for name := range s.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	statuses := make([]strategy.Status, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, s.strategies[name].Status())
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.strategies[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown strategy")
+		return
+	}
+
+	st.Pause()
+	writeJSON(w, http.StatusOK, st.Status())
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.strategies[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown strategy")
+		return
+	}
+
+	st.Resume()
+	writeJSON(w, http.StatusOK, st.Status())
+}
+
+// handleExecuteTrades toggles execute_trades. Body: {"enabled": true}
+func (s *Server) handleExecuteTrades(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.strategies[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown strategy")
+		return
+	}
+
+	toggler, ok := st.(interface{ SetExecuteTrades(bool) })
+	if !ok {
+		writeError(w, http.StatusBadRequest, "strategy does not support execute_trades")
+		return
+	}
+
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Enabled == nil {
+		writeError(w, http.StatusBadRequest, `body must be {"enabled": true|false}`)
+		return
+	}
+
+	toggler.SetExecuteTrades(*body.Enabled)
+	writeJSON(w, http.StatusOK, st.Status())
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/ledger"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/orderbook"
+	"arbitrage-bot/internal/strategy"
+)
+
+var log = logger.For("api")
+
+// Server exposes bot status and control actions as a JSON HTTP API.
+// Every request must carry "Authorization: Bearer <token>".
+type Server struct {
+	addr       string
+	token      string
+	venues     *exchange.Registry
+	strategies map[string]strategy.Strategy
+	fundingArb *strategy.FundingArbStrategy // nil when disabled
+	ledger     *ledger.Ledger
+	books      *orderbook.Aggregator // nil without WebSocket market data
+	mux        *http.ServeMux
+}
+
+func NewServer(port int, token string, venues *exchange.Registry, strategies []strategy.Strategy, ldg *ledger.Ledger, books *orderbook.Aggregator) *Server {
+	s := &Server{
+		addr:       fmt.Sprintf(":%d", port),
+		token:      token,
+		venues:     venues,
+		strategies: make(map[string]strategy.Strategy, len(strategies)),
+		ledger:     ldg,
+		books:      books,
+		mux:        http.NewServeMux(),
+	}
+	for _, st := range strategies {
+		s.strategies[st.Name()] = st
+		if arb, ok := st.(*strategy.FundingArbStrategy); ok {
+			s.fundingArb = arb
+		}
+	}
+
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/funding-rates", s.handleFundingRates)
+	s.mux.HandleFunc("GET /api/v1/opportunities", s.handleOpportunities)
+	s.mux.HandleFunc("GET /api/v1/positions", s.handlePositions)
+	s.mux.HandleFunc("GET /api/v1/orders", s.handleOrders)
+	s.mux.HandleFunc("GET /api/v1/pnl/daily", s.handleDailyPnL)
+	s.mux.HandleFunc("GET /api/v1/venues", s.handleVenues)
+	s.mux.HandleFunc("GET /api/v1/books/{symbol}", s.handleBook)
+	s.mux.HandleFunc("GET /api/v1/strategies", s.handleStrategies)
+	s.mux.HandleFunc("POST /api/v1/strategies/{name}/pause", s.handlePause)
+	s.mux.HandleFunc("POST /api/v1/strategies/{name}/resume", s.handleResume)
+	s.mux.HandleFunc("POST /api/v1/strategies/{name}/execute-trades", s.handleExecuteTrades)
+}
+
+// Start serves until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.authenticate(s.mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("HTTP API listening", "addr", s.addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("HTTP API stopped", logger.Err(err))
+	}
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid or missing API token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warn("failed to encode response", logger.Err(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
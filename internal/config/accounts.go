@@ -0,0 +1,95 @@
+package config
+
+import "strings"
+
+// Each exchange config describes a default account in its top-level fields
+// and optionally more named accounts. Every account becomes its own venue,
+// named "<exchange>" for the default and "<exchange>:<account>" otherwise.
+
+type HyperliquidAccount struct {
+	Name               string `mapstructure:"name"`
+	WalletAddress      string `mapstructure:"wallet_address"`
+	PrivateKey         Secret `mapstructure:"private_key"`
+	KeystorePassphrase Secret `mapstructure:"keystore_passphrase"`
+	VaultAddress       string `mapstructure:"vault_address"`
+}
+
+type LighterAccount struct {
+	Name         string `mapstructure:"name"`
+	APIKey       Secret `mapstructure:"api_key"`
+	PrivateKey   Secret `mapstructure:"private_key"`
+	AccountIndex int64  `mapstructure:"account_index"`
+	APIKeyIndex  uint8  `mapstructure:"api_key_index"`
+}
+
+type EdgeXAccount struct {
+	Name            string `mapstructure:"name"`
+	APIKey          Secret `mapstructure:"api_key"`
+	SecretKey       Secret `mapstructure:"secret_key"`
+	AccountID       string `mapstructure:"account_id"`
+	StarkPrivateKey Secret `mapstructure:"stark_private_key"`
+}
+
+// VenueName is the registry name of an exchange account.
+func VenueName(exchange, account string) string {
+	if account == "" {
+		return exchange
+	}
+	return exchange + ":" + account
+}
+
+// ExchangeOf returns the exchange part of a venue name.
+func ExchangeOf(venue string) string {
+	exchange, _, _ := strings.Cut(venue, ":")
+	return exchange
+}
+
+// AccountConfigs returns the default account ("") and every named account
+// as standalone configs sharing the exchange-wide settings.
+func (c HyperliquidConfig) AccountConfigs() map[string]HyperliquidConfig {
+	base := c
+	base.Accounts = nil
+
+	out := map[string]HyperliquidConfig{"": base}
+	for _, a := range c.Accounts {
+		cfg := base
+		cfg.WalletAddress = a.WalletAddress
+		cfg.PrivateKey = a.PrivateKey
+		cfg.KeystorePassphrase = a.KeystorePassphrase
+		cfg.VaultAddress = a.VaultAddress
+		out[a.Name] = cfg
+	}
+	return out
+}
+
+func (c LighterConfig) AccountConfigs() map[string]LighterConfig {
+	base := c
+	base.Accounts = nil
+
+	out := map[string]LighterConfig{"": base}
+	for _, a := range c.Accounts {
+		cfg := base
+		cfg.APIKey = a.APIKey
+		cfg.PrivateKey = a.PrivateKey
+		cfg.AccountIndex = a.AccountIndex
+		cfg.APIKeyIndex = a.APIKeyIndex
+		out[a.Name] = cfg
+	}
+	return out
+}
+
+func (c EdgeXConfig) AccountConfigs() map[string]EdgeXConfig {
+	base := c
+	base.Accounts = nil
+
+	out := map[string]EdgeXConfig{"": base}
+	for _, a := range c.Accounts {
+		cfg := base
+		cfg.APIKey = a.APIKey
+		cfg.SecretKey = a.SecretKey
+		cfg.AccountID = a.AccountID
+		cfg.StarkPrivateKey = a.StarkPrivateKey
+		out[a.Name] = cfg
+	}
+	return out
+}
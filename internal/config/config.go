@@ -11,11 +11,59 @@ type Config struct {
 	App        AppConfig        `mapstructure:"app"`
 	Exchanges  ExchangesConfig  `mapstructure:"exchanges"`
 	Strategies StrategiesConfig `mapstructure:"strategies"`
+
+	Notifications NotificationsConfig `mapstructure:"notifications"`
 }
 
 type AppConfig struct {
-	LogLevel string `mapstructure:"log_level"`
-	Port     int    `mapstructure:"port"`
+	LogLevel  string            `mapstructure:"log_level"`
+	LogFormat string            `mapstructure:"log_format"` // "console" or "json"
+	LogLevels map[string]string `mapstructure:"log_levels"` // per-module overrides
+	Port      int               `mapstructure:"port"`
+	APIToken  Secret            `mapstructure:"api_token"` // bearer token for the HTTP API
+	DataDir   string            `mapstructure:"data_dir"`  // where strategy state is persisted
+
+	MetricsPort int `mapstructure:"metrics_port"` // Prometheus /metrics, served without auth, 0 = off
+
+	FundingPollIntervalMs int `mapstructure:"funding_poll_interval_ms"`
+	MetricsPollIntervalMs int `mapstructure:"metrics_poll_interval_ms"` // balance/position refresh for /metrics
+	VenueCheckIntervalMs  int `mapstructure:"venue_check_interval_ms"`  // readiness recheck interval for every exchange
+
+	BreakerFailures   int `mapstructure:"breaker_failures"`    // consecutive failures that open a venue's breaker, 0 = off
+	BreakerCooldownMs int `mapstructure:"breaker_cooldown_ms"` // how long an open breaker skips the venue
+
+	MarketDataMaxAgeMs int `mapstructure:"market_data_max_age_ms"` // WebSocket data older than this is refetched over REST, 0 = WebSocket off
+
+	// Order book unit conversions, for venues that don't quote size in coins
+	BookUnits []BookUnit `mapstructure:"book_units"`
+
+	ShutdownTimeoutMs  int  `mapstructure:"shutdown_timeout_ms"`
+	CancelOrdersOnExit bool `mapstructure:"cancel_orders_on_exit"`
+}
+
+// BookUnit converts an exchange's order book of a pair to coins and USD per
+// coin, for venues that quote in lots (e.g. Hyperliquid's kPEPE, priced per
+// 1000 PEPE).
+type BookUnit struct {
+	Exchange     string  `mapstructure:"exchange"`
+	Symbol       string  `mapstructure:"symbol"`
+	ContractSize float64 `mapstructure:"contract_size"` // coins per unit of size
+}
+
+type NotificationsConfig struct {
+	DedupWindowMs      int          `mapstructure:"dedup_window_ms"`       // identical alerts inside this window are dropped
+	RateLimitPerMinute int          `mapstructure:"rate_limit_per_minute"` // per sink, 0 = unlimited
+	Sinks              []SinkConfig `mapstructure:"sinks"`
+}
+
+type SinkConfig struct {
+	Type        string `mapstructure:"type"`         // "webhook", "telegram", "slack" or "file"
+	MinSeverity string `mapstructure:"min_severity"` // "info", "warning" or "critical"
+
+	URL      string `mapstructure:"url"` // webhook/slack URL, or Telegram API base URL
+	BotToken Secret `mapstructure:"bot_token"`
+	ChatID   string `mapstructure:"chat_id"`
+	Path     string `mapstructure:"path"` // file sink
 }
 
 type ExchangesConfig struct {
@@ -25,25 +73,46 @@ type ExchangesConfig struct {
 }
 
 type HyperliquidConfig struct {
-	BaseURL       string `mapstructure:"base_url"`
-	APIKey        string `mapstructure:"api_key"`
-	SecretKey     string `mapstructure:"secret_key"`
-	WalletAddress string `mapstructure:"wallet_address"`
-	PrivateKey    string `mapstructure:"private_key"`
+	Enabled            bool   `mapstructure:"enabled"`
+	BaseURL            string `mapstructure:"base_url"`
+	APIKey             Secret `mapstructure:"api_key"`
+	SecretKey          Secret `mapstructure:"secret_key"`
+	WalletAddress      string `mapstructure:"wallet_address"`
+	PrivateKey         Secret `mapstructure:"private_key"`
+	KeystorePassphrase Secret `mapstructure:"keystore_passphrase"`   // for private_key: "keystore:..."
+	VaultAddress       string `mapstructure:"vault_address"`         // trade for a vault or sub-account
+	WSURL              string `mapstructure:"ws_url"`                // market data and account events, "" = REST only
+	RateLimitPerMinute int    `mapstructure:"rate_limit_per_minute"` // request weight budget, 0 = venue default
+
+	Accounts []HyperliquidAccount `mapstructure:"accounts"` // extra named accounts
 }
 
 type LighterConfig struct {
-	BaseURL    string `mapstructure:"base_url"`
-	APIKey     string `mapstructure:"api_key"`
-	PrivateKey string `mapstructure:"private_key"`
+	Enabled      bool   `mapstructure:"enabled"`
+	BaseURL      string `mapstructure:"base_url"`
+	APIKey       Secret `mapstructure:"api_key"`
+	PrivateKey   Secret `mapstructure:"private_key"`
+	AccountIndex int64  `mapstructure:"account_index"`
+	APIKeyIndex  uint8  `mapstructure:"api_key_index"`
+	WSURL        string `mapstructure:"ws_url"` // order books and account events, "" = REST only
+
+	RateLimitPerMinute int `mapstructure:"rate_limit_per_minute"` // request weight budget, 0 = venue default
+
+	Accounts []LighterAccount `mapstructure:"accounts"` // extra named accounts
 }
 
 type EdgeXConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
 	BaseURL         string `mapstructure:"base_url"`
-	APIKey          string `mapstructure:"api_key"`
-	SecretKey       string `mapstructure:"secret_key"`
+	APIKey          Secret `mapstructure:"api_key"`
+	SecretKey       Secret `mapstructure:"secret_key"`
 	AccountID       string `mapstructure:"account_id"`
-	StarkPrivateKey string `mapstructure:"stark_private_key"`
+	StarkPrivateKey Secret `mapstructure:"stark_private_key"`
+	WSURL           string `mapstructure:"ws_url"` // market data stream, "" = REST only
+
+	RateLimitPerMinute int `mapstructure:"rate_limit_per_minute"` // request weight budget, 0 = venue default
+
+	Accounts []EdgeXAccount `mapstructure:"accounts"` // extra named accounts
 }
 
 type StrategiesConfig struct {
@@ -55,13 +124,91 @@ type FundingArbConfig struct {
 	Enabled         bool     `mapstructure:"enabled"`
 	Pairs           []string `mapstructure:"pairs"`
 	MinFundingDiff  float64  `mapstructure:"min_funding_diff"`
+	ExitFundingDiff float64  `mapstructure:"exit_funding_diff"` // close an open pair once its spread drops below this
+	MaxNotional     float64  `mapstructure:"max_notional"`      // USD per leg, 0 = fixed test size
 	Leverage        float64  `mapstructure:"leverage"`
 	CheckIntervalMs int      `mapstructure:"check_interval_ms"`
+	FetchTimeoutMs  int      `mapstructure:"fetch_timeout_ms"` // per-tick deadline for funding rate requests
+	MaxQuoteAgeMs   int      `mapstructure:"max_quote_age_ms"` // older quotes are left out of spreads
 	ExecuteTrades   bool     `mapstructure:"execute_trades"`
+
+	// Account to trade per exchange, e.g. {hyperliquid: "sub1"}; unset = default
+	Accounts map[string]string `mapstructure:"accounts"`
+
+	PairOverrides []PairOverride `mapstructure:"pair_overrides"`
+}
+
+// PairOverride replaces the funding_arb defaults for one pair. Unset fields
+// keep the default.
+type PairOverride struct {
+	Symbol          string   `mapstructure:"symbol"`
+	MinFundingDiff  *float64 `mapstructure:"min_funding_diff"`
+	ExitFundingDiff *float64 `mapstructure:"exit_funding_diff"`
+	MaxNotional     *float64 `mapstructure:"max_notional"`
+	Leverage        *float64 `mapstructure:"leverage"`
+	Venues          []string `mapstructure:"venues"` // empty = all enabled exchanges
+}
+
+// PairParams are the effective funding_arb settings for one pair.
+type PairParams struct {
+	MinFundingDiff  float64  `json:"min_funding_diff"`
+	ExitFundingDiff float64  `json:"exit_funding_diff"`
+	MaxNotional     float64  `json:"max_notional"`
+	Leverage        float64  `json:"leverage"`
+	Venues          []string `json:"venues,omitempty"`
+}
+
+// Defaults returns the settings used by pairs without an override.
+func (c *FundingArbConfig) Defaults() PairParams {
+	return PairParams{
+		MinFundingDiff:  c.MinFundingDiff,
+		ExitFundingDiff: c.ExitFundingDiff,
+		MaxNotional:     c.MaxNotional,
+		Leverage:        c.Leverage,
+	}
+}
+
+// ForPair merges the override for symbol, if any, over the defaults.
+func (c *FundingArbConfig) ForPair(symbol string) PairParams {
+	p := c.Defaults()
+	for _, o := range c.PairOverrides {
+		if o.Symbol != symbol {
+			continue
+		}
+		if o.MinFundingDiff != nil {
+			p.MinFundingDiff = *o.MinFundingDiff
+		}
+		if o.ExitFundingDiff != nil {
+			p.ExitFundingDiff = *o.ExitFundingDiff
+		}
+		if o.MaxNotional != nil {
+			p.MaxNotional = *o.MaxNotional
+		}
+		if o.Leverage != nil {
+			p.Leverage = *o.Leverage
+		}
+		p.Venues = o.Venues
+	}
+	return p
+}
+
+// AllowsVenue reports whether the pair may trade on the named venue. Venues
+// are matched by exchange, whichever account they use.
+func (p PairParams) AllowsVenue(name string) bool {
+	if len(p.Venues) == 0 {
+		return true
+	}
+	for _, v := range p.Venues {
+		if v == ExchangeOf(name) {
+			return true
+		}
+	}
+	return false
 }
 
 type XPFarmingConfig struct {
 	Enabled           bool    `mapstructure:"enabled"`
+	Account           string  `mapstructure:"account"` // Hyperliquid account, "" = default
 	TargetVolumeDaily float64 `mapstructure:"target_volume_daily"`
 	MaxSlippage       float64 `mapstructure:"max_slippage"`
 }
@@ -70,17 +217,42 @@ func LoadConfig(path string) (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(path)
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
+	return decode(viper.GetViper())
+}
+
+// decode applies env overrides and defaults to a viper instance that has
+// read the config file, then resolves secrets and validates.
+func decode(v *viper.Viper) (*Config, error) {
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	// Exchanges are on unless explicitly disabled
+	for _, name := range []string{"hyperliquid", "lighter", "edgex"} {
+		v.SetDefault("exchanges."+name+".enabled", true)
+	}
+	// The Lighter account the bot originally hardcoded
+	v.SetDefault("exchanges.lighter.account_index", 1)
+	// For configs written before quotes were fetched concurrently
+	v.SetDefault("strategies.funding_arb.fetch_timeout_ms", 1500)
+	v.SetDefault("strategies.funding_arb.max_quote_age_ms", 3000)
+	v.SetDefault("app.market_data_max_age_ms", 5000)
 
 	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
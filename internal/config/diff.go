@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff describes the fields that differ between a and b, which must be the
+// same struct type, as "path: old -> new". Fields are named by their
+// mapstructure tags; string slices are reported as added/removed items.
+func Diff(prefix string, a, b any) []string {
+	var out []string
+	diffValue(prefix, reflect.ValueOf(a), reflect.ValueOf(b), &out)
+	return out
+}
+
+func diffValue(path string, a, b reflect.Value, out *[]string) {
+	switch a.Kind() {
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := t.Field(i).Tag.Get("mapstructure")
+			if name == "" {
+				name = strings.ToLower(t.Field(i).Name)
+			}
+			diffValue(join(path, name), a.Field(i), b.Field(i), out)
+		}
+
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, k := range a.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		for _, k := range b.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			av, bv := a.MapIndex(keys[name]), b.MapIndex(keys[name])
+			switch {
+			case !av.IsValid():
+				*out = append(*out, fmt.Sprintf("%s: added %v", join(path, name), bv.Interface()))
+			case !bv.IsValid():
+				*out = append(*out, fmt.Sprintf("%s: removed", join(path, name)))
+			default:
+				diffValue(join(path, name), av, bv, out)
+			}
+		}
+
+	case reflect.Slice:
+		if a.Type().Elem().Kind() == reflect.String {
+			added, removed := diffStrings(a, b)
+			if len(added) > 0 {
+				*out = append(*out, fmt.Sprintf("%s: added %v", path, added))
+			}
+			if len(removed) > 0 {
+				*out = append(*out, fmt.Sprintf("%s: removed %v", path, removed))
+			}
+			return
+		}
+		for i := 0; i < max(a.Len(), b.Len()); i++ {
+			elem := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				*out = append(*out, fmt.Sprintf("%s: added %s", elem, format(b.Index(i))))
+			case i >= b.Len():
+				*out = append(*out, fmt.Sprintf("%s: removed %s", elem, format(a.Index(i))))
+			default:
+				diffValue(elem, a.Index(i), b.Index(i), out)
+			}
+		}
+
+	case reflect.Pointer:
+		if a.IsNil() != b.IsNil() || (!a.IsNil() && !reflect.DeepEqual(a.Elem().Interface(), b.Elem().Interface())) {
+			*out = append(*out, fmt.Sprintf("%s: %s -> %s", path, format(a), format(b)))
+		}
+
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*out = append(*out, fmt.Sprintf("%s: %v -> %v", path, a.Interface(), b.Interface()))
+		}
+	}
+}
+
+// format prints v with pointers dereferenced, so optional fields show
+// their value rather than an address.
+func format(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "unset"
+		}
+		return format(v.Elem())
+	case reflect.Struct:
+		t := v.Type()
+		parts := make([]string, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := v.Field(i)
+			if f.IsZero() {
+				continue
+			}
+			parts = append(parts, t.Field(i).Tag.Get("mapstructure")+":"+format(f))
+		}
+		return "{" + strings.Join(parts, " ") + "}"
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func diffStrings(a, b reflect.Value) (added, removed []string) {
+	inA := make(map[string]bool, a.Len())
+	for i := 0; i < a.Len(); i++ {
+		inA[a.Index(i).String()] = true
+	}
+	inB := make(map[string]bool, b.Len())
+	for i := 0; i < b.Len(); i++ {
+		s := b.Index(i).String()
+		inB[s] = true
+		if !inA[s] {
+			added = append(added, s)
+		}
+	}
+	for i := 0; i < a.Len(); i++ {
+		if s := a.Index(i).String(); !inB[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Secret is a credential from the config. It prints as [REDACTED] through
+// fmt, slog and JSON; use string(s) where the real value is needed.
+//
+// In config.yaml a secret is either the literal value or a reference:
+//
+//	env:NAME        value of environment variable NAME
+//	file:PATH       contents of PATH, surrounding whitespace trimmed
+//	keystore:PATH   private key decrypted from a geth-style keystore file
+//	                (only for Hyperliquid private keys, using keystore_passphrase)
+type Secret string
+
+const redacted = "[REDACTED]"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// resolve turns a secret reference into its value. passphrase is only used
+// for keystore references.
+func (s Secret) resolve(passphrase Secret) (Secret, error) {
+	ref := string(s)
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		val, ok := os.LookupEnv(name)
+		if !ok || val == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return Secret(val), nil
+
+	case strings.HasPrefix(ref, "file:"):
+		path := strings.TrimPrefix(ref, "file:")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return Secret(strings.TrimSpace(string(data))), nil
+
+	case strings.HasPrefix(ref, "keystore:"):
+		path := strings.TrimPrefix(ref, "keystore:")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read keystore: %w", err)
+		}
+		if passphrase == "" {
+			return "", fmt.Errorf("keystore %s needs a passphrase", path)
+		}
+		key, err := keystore.DecryptKey(data, string(passphrase))
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+		}
+		return Secret(hex.EncodeToString(crypto.FromECDSA(key.PrivateKey))), nil
+	}
+	return s, nil
+}
+
+// resolveSecrets replaces every secret reference in the config with its
+// value, collecting all failures.
+func (c *Config) resolveSecrets() error {
+	v := &validator{}
+
+	resolve := func(field string, s *Secret) bool {
+		if strings.HasPrefix(string(*s), "keystore:") {
+			v.addf(field, "keystore references are only supported for hyperliquid private keys")
+			return false
+		}
+		val, err := s.resolve("")
+		if err != nil {
+			v.addf(field, "%v", err)
+			return false
+		}
+		*s = val
+		return true
+	}
+
+	// Hyperliquid keys may also come from a keystore
+	resolveKey := func(field string, key, passphrase *Secret) {
+		if !resolve(field+".keystore_passphrase", passphrase) {
+			return
+		}
+		val, err := key.resolve(*passphrase)
+		if err != nil {
+			v.addf(field+".private_key", "%v", err)
+			return
+		}
+		*key = val
+	}
+
+	hl := &c.Exchanges.Hyperliquid
+	resolveKey("exchanges.hyperliquid", &hl.PrivateKey, &hl.KeystorePassphrase)
+	resolve("exchanges.hyperliquid.api_key", &hl.APIKey)
+	resolve("exchanges.hyperliquid.secret_key", &hl.SecretKey)
+	for i := range hl.Accounts {
+		a := &hl.Accounts[i]
+		resolveKey(fmt.Sprintf("exchanges.hyperliquid.accounts[%d]", i), &a.PrivateKey, &a.KeystorePassphrase)
+	}
+
+	lt := &c.Exchanges.Lighter
+	resolve("exchanges.lighter.api_key", &lt.APIKey)
+	resolve("exchanges.lighter.private_key", &lt.PrivateKey)
+	for i := range lt.Accounts {
+		a := &lt.Accounts[i]
+		resolve(fmt.Sprintf("exchanges.lighter.accounts[%d].api_key", i), &a.APIKey)
+		resolve(fmt.Sprintf("exchanges.lighter.accounts[%d].private_key", i), &a.PrivateKey)
+	}
+
+	ex := &c.Exchanges.EdgeX
+	resolve("exchanges.edgex.api_key", &ex.APIKey)
+	resolve("exchanges.edgex.secret_key", &ex.SecretKey)
+	resolve("exchanges.edgex.stark_private_key", &ex.StarkPrivateKey)
+	for i := range ex.Accounts {
+		a := &ex.Accounts[i]
+		resolve(fmt.Sprintf("exchanges.edgex.accounts[%d].api_key", i), &a.APIKey)
+		resolve(fmt.Sprintf("exchanges.edgex.accounts[%d].secret_key", i), &a.SecretKey)
+		resolve(fmt.Sprintf("exchanges.edgex.accounts[%d].stark_private_key", i), &a.StarkPrivateKey)
+	}
+
+	resolve("app.api_token", &c.App.APIToken)
+	for i := range c.Notifications.Sinks {
+		resolve(fmt.Sprintf("notifications.sinks[%d].bot_token", i), &c.Notifications.Sinks[i].BotToken)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
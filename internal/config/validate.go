@@ -0,0 +1,463 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"arbitrage-bot/internal/logger"
+)
+
+// ValidationError lists every problem found in a config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Pairs are "<BASE>-USD", e.g. "ETH-USD"
+var pairPattern = regexp.MustCompile(`^[A-Z0-9]+-USD$`)
+
+// Hex-encoded secp256k1 key, optional 0x prefix
+var hexKeyPattern = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{64}$`)
+
+// Pairs every adapter can map. Lighter's market index table is the
+// limiting one; extend both together.
+var knownPairs = map[string]bool{
+	"ETH-USD":  true,
+	"BTC-USD":  true,
+	"SOL-USD":  true,
+	"AVAX-USD": true,
+}
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(field, format string, args ...any) {
+	v.problems = append(v.problems, field+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) url(field, value string) {
+	if value == "" {
+		v.addf(field, "required")
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.addf(field, "%q is not a valid http(s) URL", value)
+	}
+}
+
+// wsURL checks an optional WebSocket URL.
+func (v *validator) wsURL(field, value string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+		v.addf(field, "%q is not a valid ws(s) URL", value)
+	}
+}
+
+func (v *validator) required(field, value string) {
+	if value == "" {
+		v.addf(field, "required")
+	}
+}
+
+func (v *validator) nonNegative(field string, value int) {
+	if value < 0 {
+		v.addf(field, "must be >= 0, got %d", value)
+	}
+}
+
+// Validate checks the whole config and returns a *ValidationError listing
+// every problem, or nil.
+func (c *Config) Validate() error {
+	v := &validator{}
+	c.App.validate(v)
+	c.Exchanges.validate(v)
+	c.Strategies.validate(v, &c.Exchanges)
+	c.Notifications.validate(v)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (a *AppConfig) validate(v *validator) {
+	if _, err := logger.ParseLevel(a.LogLevel); err != nil {
+		v.addf("app.log_level", "%v", err)
+	}
+	if a.LogFormat != "" && a.LogFormat != "console" && a.LogFormat != "json" {
+		v.addf("app.log_format", "must be \"console\" or \"json\", got %q", a.LogFormat)
+	}
+	for module, level := range a.LogLevels {
+		if _, err := logger.ParseLevel(level); err != nil {
+			v.addf("app.log_levels."+module, "%v", err)
+		}
+	}
+	if a.APIToken != "" && (a.Port <= 0 || a.Port > 65535) {
+		v.addf("app.port", "must be 1-65535, got %d", a.Port)
+	}
+	if a.MetricsPort < 0 || a.MetricsPort > 65535 {
+		v.addf("app.metrics_port", "must be 0-65535, got %d", a.MetricsPort)
+	} else if a.MetricsPort != 0 && a.APIToken != "" && a.MetricsPort == a.Port {
+		v.addf("app.metrics_port", "must differ from app.port")
+	}
+	v.required("app.data_dir", a.DataDir)
+	v.nonNegative("app.funding_poll_interval_ms", a.FundingPollIntervalMs)
+	v.nonNegative("app.metrics_poll_interval_ms", a.MetricsPollIntervalMs)
+	v.nonNegative("app.venue_check_interval_ms", a.VenueCheckIntervalMs)
+	v.nonNegative("app.breaker_failures", a.BreakerFailures)
+	if a.BreakerFailures > 0 && a.BreakerCooldownMs <= 0 {
+		v.addf("app.breaker_cooldown_ms", "must be > 0 when app.breaker_failures is set, got %d", a.BreakerCooldownMs)
+	}
+	v.nonNegative("app.market_data_max_age_ms", a.MarketDataMaxAgeMs)
+	seen := make(map[BookUnit]bool)
+	for i, u := range a.BookUnits {
+		field := fmt.Sprintf("app.book_units[%d]", i)
+		switch u.Exchange {
+		case "hyperliquid", "lighter", "edgex":
+		default:
+			v.addf(field+".exchange", "unknown exchange %q", u.Exchange)
+		}
+		v.required(field+".symbol", u.Symbol)
+		if u.ContractSize <= 0 {
+			v.addf(field+".contract_size", "must be > 0, got %g", u.ContractSize)
+		}
+		key := BookUnit{Exchange: u.Exchange, Symbol: u.Symbol}
+		if seen[key] {
+			v.addf(field, "duplicate entry for %s %s", u.Exchange, u.Symbol)
+		}
+		seen[key] = true
+	}
+	v.nonNegative("app.shutdown_timeout_ms", a.ShutdownTimeoutMs)
+}
+
+// enabledCount returns how many exchanges are enabled.
+func (e *ExchangesConfig) enabledCount() int {
+	n := 0
+	for _, enabled := range []bool{e.Hyperliquid.Enabled, e.Lighter.Enabled, e.EdgeX.Enabled} {
+		if enabled {
+			n++
+		}
+	}
+	return n
+}
+
+func (e *ExchangesConfig) validate(v *validator) {
+	if e.Hyperliquid.Enabled {
+		v.url("exchanges.hyperliquid.base_url", e.Hyperliquid.BaseURL)
+		v.wsURL("exchanges.hyperliquid.ws_url", e.Hyperliquid.WSURL)
+	}
+	if e.Lighter.Enabled {
+		v.url("exchanges.lighter.base_url", e.Lighter.BaseURL)
+		v.wsURL("exchanges.lighter.ws_url", e.Lighter.WSURL)
+	}
+	if e.EdgeX.Enabled {
+		v.url("exchanges.edgex.base_url", e.EdgeX.BaseURL)
+		v.wsURL("exchanges.edgex.ws_url", e.EdgeX.WSURL)
+	}
+
+	v.nonNegative("exchanges.hyperliquid.rate_limit_per_minute", e.Hyperliquid.RateLimitPerMinute)
+	v.nonNegative("exchanges.lighter.rate_limit_per_minute", e.Lighter.RateLimitPerMinute)
+	v.nonNegative("exchanges.edgex.rate_limit_per_minute", e.EdgeX.RateLimitPerMinute)
+
+	hl := e.Hyperliquid
+	validateHyperliquidAccount(v, "exchanges.hyperliquid", hl.PrivateKey, hl.WalletAddress, hl.VaultAddress)
+	for i, a := range hl.Accounts {
+		field := fmt.Sprintf("exchanges.hyperliquid.accounts[%d]", i)
+		validateHyperliquidAccount(v, field, a.PrivateKey, a.WalletAddress, a.VaultAddress)
+	}
+
+	lt := e.Lighter
+	if lt.AccountIndex <= 0 {
+		v.addf("exchanges.lighter.account_index", "must be > 0, got %d", lt.AccountIndex)
+	}
+	for i, a := range lt.Accounts {
+		if a.AccountIndex <= 0 {
+			v.addf(fmt.Sprintf("exchanges.lighter.accounts[%d].account_index", i), "must be > 0, got %d", a.AccountIndex)
+		}
+	}
+
+	for _, ex := range []struct {
+		name  string
+		names []string
+	}{
+		{"hyperliquid", e.accountNames("hyperliquid")},
+		{"lighter", e.accountNames("lighter")},
+		{"edgex", e.accountNames("edgex")},
+	} {
+		seen := make(map[string]bool)
+		for i, name := range ex.names {
+			field := fmt.Sprintf("exchanges.%s.accounts[%d].name", ex.name, i)
+			switch {
+			case name == "":
+				v.addf(field, "required")
+			case !accountNamePattern.MatchString(name):
+				v.addf(field, "%q may only contain letters, digits, '-' and '_'", name)
+			case seen[name]:
+				v.addf(field, "%q is used twice", name)
+			}
+			seen[name] = true
+		}
+	}
+}
+
+var accountNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func validateHyperliquidAccount(v *validator, field string, key Secret, wallet, vault string) {
+	if key != "" && !hexKeyPattern.MatchString(string(key)) {
+		v.addf(field+".private_key", "must be 64 hex characters")
+	}
+	if wallet != "" && !addressPattern.MatchString(wallet) {
+		v.addf(field+".wallet_address", "%q is not a 0x-prefixed address", wallet)
+	}
+	if vault != "" && !addressPattern.MatchString(vault) {
+		v.addf(field+".vault_address", "%q is not a 0x-prefixed address", vault)
+	}
+}
+
+// accountNames lists the named accounts of an exchange, in config order.
+func (e *ExchangesConfig) accountNames(exchange string) []string {
+	var names []string
+	switch exchange {
+	case "hyperliquid":
+		for _, a := range e.Hyperliquid.Accounts {
+			names = append(names, a.Name)
+		}
+	case "lighter":
+		for _, a := range e.Lighter.Accounts {
+			names = append(names, a.Name)
+		}
+	case "edgex":
+		for _, a := range e.EdgeX.Accounts {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// accountField returns the config path of an account ("" = default), or
+// false if the exchange has no such account.
+func (e *ExchangesConfig) accountField(exchange, account string) (string, bool) {
+	if account == "" {
+		return "exchanges." + exchange, true
+	}
+	for i, name := range e.accountNames(exchange) {
+		if name == account {
+			return fmt.Sprintf("exchanges.%s.accounts[%d]", exchange, i), true
+		}
+	}
+	return "", false
+}
+
+// tradingCredentials reports missing credentials needed to place orders
+// with the given account (by exchange) on each enabled exchange.
+func (e *ExchangesConfig) tradingCredentials(v *validator, accounts map[string]string, reason string) {
+	if e.Hyperliquid.Enabled {
+		e.hyperliquidCredentials(v, accounts["hyperliquid"], reason)
+	}
+	if e.Lighter.Enabled {
+		cfg := e.Lighter.AccountConfigs()[accounts["lighter"]]
+		field, _ := e.accountField("lighter", accounts["lighter"])
+		if cfg.PrivateKey == "" {
+			v.addf(field+".private_key", "required when %s", reason)
+		}
+	}
+	if e.EdgeX.Enabled {
+		cfg := e.EdgeX.AccountConfigs()[accounts["edgex"]]
+		field, _ := e.accountField("edgex", accounts["edgex"])
+		if cfg.AccountID == "" {
+			v.addf(field+".account_id", "required when %s", reason)
+		}
+		if cfg.StarkPrivateKey == "" {
+			v.addf(field+".stark_private_key", "required when %s", reason)
+		}
+	}
+}
+
+func (e *ExchangesConfig) hyperliquidCredentials(v *validator, account, reason string) {
+	cfg := e.Hyperliquid.AccountConfigs()[account]
+	field, _ := e.accountField("hyperliquid", account)
+	if cfg.PrivateKey == "" {
+		v.addf(field+".private_key", "required when %s", reason)
+	}
+}
+
+// validateAccounts checks that a strategy only targets accounts that exist
+// on enabled exchanges.
+func (e *ExchangesConfig) validateAccounts(v *validator, field string, accounts map[string]string) {
+	enabled := map[string]bool{
+		"hyperliquid": e.Hyperliquid.Enabled,
+		"lighter":     e.Lighter.Enabled,
+		"edgex":       e.EdgeX.Enabled,
+	}
+	exchanges := make([]string, 0, len(accounts))
+	for exchange := range accounts {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	for _, exchange := range exchanges {
+		account := accounts[exchange]
+		on, known := enabled[exchange]
+		switch {
+		case !known:
+			v.addf(field+"."+exchange, "unknown exchange %q", exchange)
+		case !on:
+			v.addf(field+"."+exchange, "exchange %q is not enabled", exchange)
+		default:
+			if _, ok := e.accountField(exchange, account); !ok {
+				v.addf(field+"."+exchange, "exchange %q has no account %q", exchange, account)
+			}
+		}
+	}
+}
+
+func (s *StrategiesConfig) validate(v *validator, exchanges *ExchangesConfig) {
+	if f := s.FundingArb; f.Enabled {
+		if exchanges.enabledCount() < 2 {
+			v.addf("strategies.funding_arb", "needs at least two enabled exchanges")
+		}
+		if len(f.Pairs) == 0 {
+			v.addf("strategies.funding_arb.pairs", "at least one pair is required")
+		}
+		seen := make(map[string]bool)
+		for _, p := range f.Pairs {
+			if !pairPattern.MatchString(p) {
+				v.addf("strategies.funding_arb.pairs", "%q is not a valid pair, expected e.g. \"ETH-USD\"", p)
+			} else if !knownPairs[p] {
+				v.addf("strategies.funding_arb.pairs", "%q is not supported on every exchange", p)
+			}
+			if seen[p] {
+				v.addf("strategies.funding_arb.pairs", "%q is listed twice", p)
+			}
+			seen[p] = true
+		}
+		validatePairParams(v, "strategies.funding_arb", f.Defaults())
+		f.validateOverrides(v, exchanges, seen)
+		if f.CheckIntervalMs <= 0 {
+			v.addf("strategies.funding_arb.check_interval_ms", "must be > 0, got %d", f.CheckIntervalMs)
+		}
+		if f.FetchTimeoutMs <= 0 {
+			v.addf("strategies.funding_arb.fetch_timeout_ms", "must be > 0, got %d", f.FetchTimeoutMs)
+		}
+		// Otherwise even quotes fetched in time could be discarded
+		if f.MaxQuoteAgeMs < f.FetchTimeoutMs {
+			v.addf("strategies.funding_arb.max_quote_age_ms", "must be >= fetch_timeout_ms (%d), got %d", f.FetchTimeoutMs, f.MaxQuoteAgeMs)
+		}
+		exchanges.validateAccounts(v, "strategies.funding_arb.accounts", f.Accounts)
+		if f.ExecuteTrades {
+			exchanges.tradingCredentials(v, f.Accounts, "strategies.funding_arb.execute_trades is true")
+		}
+	}
+
+	if x := s.XPFarming; x.Enabled {
+		if x.TargetVolumeDaily <= 0 {
+			v.addf("strategies.xp_farming.target_volume_daily", "must be > 0, got %g", x.TargetVolumeDaily)
+		}
+		if x.MaxSlippage < 0 || x.MaxSlippage >= 0.1 {
+			v.addf("strategies.xp_farming.max_slippage", "must be between 0 and 0.1, got %g", x.MaxSlippage)
+		}
+		// XP farming trades on Hyperliquid only
+		if !exchanges.Hyperliquid.Enabled {
+			v.addf("exchanges.hyperliquid.enabled", "must be true when strategies.xp_farming is enabled")
+		} else if _, ok := exchanges.accountField("hyperliquid", x.Account); !ok {
+			v.addf("strategies.xp_farming.account", "exchange %q has no account %q", "hyperliquid", x.Account)
+		} else {
+			exchanges.hyperliquidCredentials(v, x.Account, "strategies.xp_farming is enabled")
+		}
+	}
+}
+
+func validatePairParams(v *validator, field string, p PairParams) {
+	if p.MinFundingDiff <= 0 || p.MinFundingDiff >= 1 {
+		v.addf(field+".min_funding_diff", "must be between 0 and 1, got %g", p.MinFundingDiff)
+	}
+	if p.ExitFundingDiff >= p.MinFundingDiff {
+		v.addf(field+".exit_funding_diff", "must be below min_funding_diff (%g), got %g", p.MinFundingDiff, p.ExitFundingDiff)
+	}
+	if p.MaxNotional < 0 {
+		v.addf(field+".max_notional", "must be >= 0, got %g", p.MaxNotional)
+	}
+	if p.Leverage <= 0 {
+		v.addf(field+".leverage", "must be > 0, got %g", p.Leverage)
+	}
+}
+
+// validateOverrides checks each override and the settings it produces
+// once merged over the defaults.
+func (f *FundingArbConfig) validateOverrides(v *validator, exchanges *ExchangesConfig, pairs map[string]bool) {
+	enabled := map[string]bool{
+		"hyperliquid": exchanges.Hyperliquid.Enabled,
+		"lighter":     exchanges.Lighter.Enabled,
+		"edgex":       exchanges.EdgeX.Enabled,
+	}
+
+	overridden := make(map[string]bool)
+	for i, o := range f.PairOverrides {
+		field := fmt.Sprintf("strategies.funding_arb.pair_overrides[%d]", i)
+		switch {
+		case o.Symbol == "":
+			v.addf(field+".symbol", "required")
+			continue
+		case !pairs[o.Symbol]:
+			v.addf(field+".symbol", "%q is not in strategies.funding_arb.pairs", o.Symbol)
+		case overridden[o.Symbol]:
+			v.addf(field+".symbol", "%q already has an override", o.Symbol)
+		}
+		overridden[o.Symbol] = true
+
+		if len(o.Venues) == 1 {
+			v.addf(field+".venues", "needs at least two exchanges")
+		}
+		for _, name := range o.Venues {
+			known, ok := enabled[name]
+			if !ok {
+				v.addf(field+".venues", "unknown exchange %q", name)
+			} else if !known {
+				v.addf(field+".venues", "exchange %q is not enabled", name)
+			}
+		}
+
+		validatePairParams(v, field, f.ForPair(o.Symbol))
+	}
+}
+
+func (n *NotificationsConfig) validate(v *validator) {
+	v.nonNegative("notifications.dedup_window_ms", n.DedupWindowMs)
+	v.nonNegative("notifications.rate_limit_per_minute", n.RateLimitPerMinute)
+
+	for i, sc := range n.Sinks {
+		field := fmt.Sprintf("notifications.sinks[%d]", i)
+		switch strings.ToLower(sc.MinSeverity) {
+		case "", "info", "warn", "warning", "critical", "error":
+		default:
+			v.addf(field+".min_severity", "unknown severity %q", sc.MinSeverity)
+		}
+
+		switch sc.Type {
+		case "webhook", "slack":
+			v.url(field+".url", sc.URL)
+		case "telegram":
+			v.required(field+".bot_token", string(sc.BotToken))
+			v.required(field+".chat_id", sc.ChatID)
+			if sc.URL != "" {
+				v.url(field+".url", sc.URL)
+			}
+		case "file":
+			v.required(field+".path", sc.Path)
+		default:
+			v.addf(field+".type", "must be webhook, telegram, slack or file, got %q", sc.Type)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"arbitrage-bot/internal/logger"
+)
+
+var log = logger.For("config")
+
+// Editors often write a file in several steps; wait for it to settle
+const reloadDebounce = 500 * time.Millisecond
+
+// WatchStrategies reloads the config file whenever it changes. If the new
+// file validates, apply is called with the old and new strategies sections;
+// otherwise the problems are logged and the running config is kept.
+// Changes outside strategies need a restart and are only logged.
+//
+// Must be called after LoadConfig.
+func WatchStrategies(current *Config, apply func(old, new StrategiesConfig)) {
+	path := viper.ConfigFileUsed()
+	active := *current
+
+	var mu sync.Mutex
+	var timer *time.Timer
+
+	reloadNow := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		next, err := reload(path)
+		if err != nil {
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				log.Error("config change rejected, keeping running config", "problems", verr.Problems)
+			} else {
+				log.Error("config change rejected, keeping running config", logger.Err(err))
+			}
+			return
+		}
+
+		for _, section := range []struct {
+			name     string
+			old, new any
+		}{
+			{"app", active.App, next.App},
+			{"exchanges", active.Exchanges, next.Exchanges},
+			{"notifications", active.Notifications, next.Notifications},
+		} {
+			if !reflect.DeepEqual(section.old, section.new) {
+				log.Warn("config section changed, restart to apply", "section", section.name)
+			}
+		}
+
+		changes := Diff("strategies", active.Strategies, next.Strategies)
+		if len(changes) == 0 {
+			return
+		}
+		log.Info("strategies config reloaded", "changes", changes)
+
+		apply(active.Strategies, next.Strategies)
+		active = *next
+	}
+
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(reloadDebounce, reloadNow)
+	})
+	viper.WatchConfig()
+}
+
+// reload reads path with a fresh viper instance, so it doesn't race with
+// the watcher's own reads of the global one.
+func reload(path string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return decode(v)
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/metrics"
+)
+
+var log = logger.For("events")
+
+// Bus fans events out to subscribers. Publishing never blocks: a subscriber
+// whose buffer is full misses the event, which is counted and logged as a
+// slow consumer. A nil *Bus drops everything.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// Subscription receives the events of the kinds it was created for.
+type Subscription struct {
+	name  string
+	kinds map[Kind]bool // all kinds if empty
+	ch    chan Event
+
+	dropped atomic.Uint64
+	slow    atomic.Bool // dropping since the last delivered event
+}
+
+// NewBus creates a bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe returns a subscription named name (for logs and metrics)
+// buffering up to buffer events of the given kinds, or of every kind if none
+// are given. Returns nil on a nil bus.
+func (b *Bus) Subscribe(name string, buffer int, kinds ...Kind) *Subscription {
+	if b == nil {
+		return nil
+	}
+	s := &Subscription{
+		name:  name,
+		kinds: make(map[Kind]bool, len(kinds)),
+		ch:    make(chan Event, buffer),
+	}
+	for _, k := range kinds {
+		s.kinds[k] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Unsubscribe stops delivery to s and closes its channel.
+func (b *Bus) Unsubscribe(s *Subscription) {
+	if b == nil || s == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		close(s.ch)
+	}
+}
+
+// Publish delivers e to every subscriber of its kind that has room for it.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	kind := e.Kind()
+	metrics.EventsPublished.WithLabelValues(string(kind)).Inc()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subs {
+		if len(s.kinds) > 0 && !s.kinds[kind] {
+			continue
+		}
+		s.deliver(kind, e)
+	}
+}
+
+func (s *Subscription) deliver(kind Kind, e Event) {
+	select {
+	case s.ch <- e:
+		if s.slow.Swap(false) {
+			log.Info("subscriber caught up", "subscriber", s.name, "dropped", s.dropped.Load())
+		}
+	default:
+		s.dropped.Add(1)
+		metrics.EventsDropped.WithLabelValues(s.name, string(kind)).Inc()
+		// Once per episode, the buffer may stay full for a while
+		if !s.slow.Swap(true) {
+			log.Warn("slow subscriber, dropping events", "subscriber", s.name, "buffer", cap(s.ch), "kind", kind)
+		}
+	}
+}
+
+// Events returns the channel events are delivered on. It is closed by
+// Unsubscribe. A nil subscription never delivers.
+func (s *Subscription) Events() <-chan Event {
+	if s == nil {
+		return nil
+	}
+	return s.ch
+}
+
+// Dropped returns the number of events missed because the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.dropped.Load()
+}
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/marketdata"
+)
+
+// Kind identifies an event type, for subscribing to some of them.
+type Kind string
+
+const (
+	KindFunding     Kind = "funding"
+	KindBook        Kind = "book"
+	KindOrder       Kind = "order"
+	KindFill        Kind = "fill"
+	KindPosition    Kind = "position"
+	KindVenueStatus Kind = "venue_status"
+)
+
+// Event is one of the event types below.
+type Event interface {
+	Kind() Kind
+}
+
+// FundingUpdate is a new funding rate of a pair, pushed by a venue's market
+// stream. Market data is the same for every account, so it names the
+// exchange rather than a venue.
+type FundingUpdate struct {
+	Exchange string
+	Symbol   string
+	Rate     float64
+	Time     time.Time
+}
+
+// BookUpdate is the new top of an order book, pushed by a venue's market
+// stream.
+type BookUpdate struct {
+	Exchange string
+	Symbol   string
+	Book     marketdata.Book
+}
+
+// OrderUpdate is a status change of one of our orders, pushed by a venue's
+// user stream or returned when the order is placed or cancelled.
+type OrderUpdate struct {
+	Venue   string
+	OrderID string
+	Symbol  string
+	Side    string  // "buy" or "sell"
+	Status  string  // "open", "filled", "canceled", "rejected", ... as the venue reports it
+	Size    float64 // remaining
+	Price   float64
+	Time    time.Time
+}
+
+// Fill is an execution of one of our orders, pushed by a venue's user
+// stream.
+type Fill struct {
+	Venue   string
+	OrderID string
+	TradeID string // the venue's, to tell replayed fills apart
+	Symbol  string
+	Side    string // "buy" or "sell"
+	Size    float64
+	Price   float64
+	Fee     float64
+	Time    time.Time
+}
+
+// PositionUpdate is the new state of one of our positions, pushed by a
+// venue's user stream. A closed position has size 0.
+type PositionUpdate struct {
+	Venue    string
+	Position exchange.Position
+	Time     time.Time
+}
+
+// VenueStatus is a change in a venue's readiness or circuit breaker.
+type VenueStatus struct {
+	Venue  string
+	Status exchange.VenueStatus
+}
+
+func (FundingUpdate) Kind() Kind  { return KindFunding }
+func (BookUpdate) Kind() Kind     { return KindBook }
+func (OrderUpdate) Kind() Kind    { return KindOrder }
+func (Fill) Kind() Kind           { return KindFill }
+func (PositionUpdate) Kind() Kind { return KindPosition }
+func (VenueStatus) Kind() Kind    { return KindVenueStatus }
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+)
+
+// publishing wraps an Exchange, publishing the orders it places and cancels
+// as order updates. Fills are left to the user streams, which report each
+// execution once.
+type publishing struct {
+	exchange.Exchange
+	venue string
+	bus   *Bus
+}
+
+// Publish wraps exc so its order results are published on bus under the
+// given venue name.
+func Publish(bus *Bus, venue string, exc exchange.Exchange) exchange.Exchange {
+	return &publishing{Exchange: exc, venue: venue, bus: bus}
+}
+
+func (p *publishing) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	res, err := p.Exchange.PlaceOrder(req)
+	if err != nil {
+		return res, err
+	}
+	p.bus.Publish(OrderUpdate{
+		Venue:   p.venue,
+		OrderID: res.OrderID,
+		Symbol:  req.Symbol,
+		Side:    req.Side,
+		Status:  res.Status,
+		Size:    max(req.Size-res.FilledSize, 0),
+		Price:   req.Price,
+		Time:    time.Now(),
+	})
+	return res, nil
+}
+
+func (p *publishing) CancelOrder(symbol, orderID string) error {
+	if err := p.Exchange.CancelOrder(symbol, orderID); err != nil {
+		return err
+	}
+	p.bus.Publish(OrderUpdate{
+		Venue:   p.venue,
+		OrderID: orderID,
+		Symbol:  symbol,
+		Status:  "canceled",
+		Time:    time.Now(),
+	})
+	return nil
+}
@@ -0,0 +1,263 @@
+package exchange
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
+)
+
+// ErrCircuitOpen is returned without calling the venue while its breaker is
+// open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerState is the state of a venue's circuit breaker.
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // calls go through
+	BreakerHalfOpen                     // one probe call at a time
+	BreakerOpen                         // calls fail fast until the cool-down ends
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerHalfOpen:
+		return "half_open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+func (s BreakerState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Breaker wraps an Exchange and stops calling it after consecutive failures
+// that point at the venue being unavailable (see Retryable), so a dead venue
+// doesn't cost every caller a full HTTP timeout. After the cool-down a single
+// probe call is let through; its outcome closes or reopens the breaker.
+type Breaker struct {
+	venue     string
+	next      Exchange
+	threshold int
+	cooldown  time.Duration
+
+	// OnChange, if set, is called on every state transition. It runs under
+	// the breaker's lock and must not call back into it.
+	OnChange func(venue string, state BreakerState)
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker wraps next, opening after threshold consecutive failures for
+// cooldown.
+func NewBreaker(venue string, next Exchange, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{venue: venue, next: next, threshold: threshold, cooldown: cooldown}
+}
+
+var _ Exchange = (*Breaker)(nil)
+
+// State returns the current state.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow reports whether a call would currently be let through.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		return time.Since(b.openedAt) >= b.cooldown
+	case BreakerHalfOpen:
+		return !b.probing
+	default:
+		return true
+	}
+}
+
+// before admits or rejects a call, moving to half-open once the cool-down
+// has passed.
+func (b *Breaker) before() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// after records the outcome of an admitted call. Errors that don't say the
+// venue is unavailable (e.g. an invalid order) count as a response.
+func (b *Breaker) after(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := Retryable(err)
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probing = false
+		if failed {
+			b.open()
+		} else {
+			b.failures = 0
+			b.setState(BreakerClosed)
+		}
+	case BreakerClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	}
+}
+
+func (b *Breaker) open() {
+	b.openedAt = time.Now()
+	b.setState(BreakerOpen)
+}
+
+func (b *Breaker) setState(state BreakerState) {
+	if state == b.state {
+		return
+	}
+	b.state = state
+	log.Info("circuit breaker state changed", logger.KeyVenue, b.venue, "state", state)
+	if b.OnChange != nil {
+		b.OnChange(b.venue, state)
+	}
+}
+
+func (b *Breaker) GetFundingRate(symbol string) (float64, error) {
+	if err := b.before(); err != nil {
+		return 0, err
+	}
+	rate, err := b.next.GetFundingRate(symbol)
+	b.after(err)
+	return rate, err
+}
+
+func (b *Breaker) GetPrice(symbol string) (float64, error) {
+	if err := b.before(); err != nil {
+		return 0, err
+	}
+	price, err := b.next.GetPrice(symbol)
+	b.after(err)
+	return price, err
+}
+
+func (b *Breaker) GetFundingRates(symbols []string) (map[string]float64, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	rates, err := b.next.GetFundingRates(symbols)
+	b.after(err)
+	return rates, err
+}
+
+func (b *Breaker) GetPrices(symbols []string) (map[string]float64, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	prices, err := b.next.GetPrices(symbols)
+	b.after(err)
+	return prices, err
+}
+
+func (b *Breaker) GetOrderBook(symbol string) (*marketdata.Book, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	book, err := b.next.GetOrderBook(symbol)
+	b.after(err)
+	return book, err
+}
+
+func (b *Breaker) GetBalance(asset string) (float64, error) {
+	if err := b.before(); err != nil {
+		return 0, err
+	}
+	balance, err := b.next.GetBalance(asset)
+	b.after(err)
+	return balance, err
+}
+
+func (b *Breaker) GetPosition(symbol string) (*Position, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	pos, err := b.next.GetPosition(symbol)
+	b.after(err)
+	return pos, err
+}
+
+func (b *Breaker) GetPositions() ([]*Position, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	positions, err := b.next.GetPositions()
+	b.after(err)
+	return positions, err
+}
+
+func (b *Breaker) GetOpenOrders() ([]*Order, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	orders, err := b.next.GetOpenOrders()
+	b.after(err)
+	return orders, err
+}
+
+func (b *Breaker) GetFundingPayments(since time.Time) ([]*FundingPayment, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	payments, err := b.next.GetFundingPayments(since)
+	b.after(err)
+	return payments, err
+}
+
+func (b *Breaker) PlaceOrder(req *OrderRequest) (*OrderResponse, error) {
+	if err := b.before(); err != nil {
+		return nil, err
+	}
+	res, err := b.next.PlaceOrder(req)
+	b.after(err)
+	return res, err
+}
+
+func (b *Breaker) CancelOrder(symbol, orderID string) error {
+	if err := b.before(); err != nil {
+		return err
+	}
+	err := b.next.CancelOrder(symbol, orderID)
+	b.after(err)
+	return err
+}
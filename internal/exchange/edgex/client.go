@@ -5,22 +5,39 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	edgexsdk "github.com/edgex-Tech/edgex-golang-sdk/sdk"
+	"github.com/edgex-Tech/edgex-golang-sdk/sdk/account"
 
 	"arbitrage-bot/internal/config"
 	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
 )
 
+var log = logger.For("edgex")
+
 type Client struct {
 	cfg        config.EdgeXConfig
 	httpClient *http.Client
-	metadata   *MetadataResponse
 	sdkClient  *edgexsdk.Client
+	limiter    *exchange.Limiter
+	market     *marketdata.Cache // fed by Feed, nil without one
+
+	mu       sync.RWMutex
+	metadata *MetadataResponse // nil until fetchMetadata succeeds
+
+	// Last funding data by contract ID; see latestFunding
+	snapMu     sync.Mutex
+	snapshot   map[string]FundingRateData
+	snapshotAt time.Time
 }
 
 // EdgeX API Response structures
@@ -41,6 +58,14 @@ type FundingRateData struct {
 	FundingTimestamp string `json:"fundingTimestamp"`
 }
 
+type TickerData struct {
+	ContractId string `json:"contractId"`
+	LastPrice  string `json:"lastPrice"`
+}
+
+// Position transaction type for funding settlements
+const fundingSettleType = "SETTLE_FUNDING_FEE"
+
 type MetadataResponse struct {
 	Global       GlobalConfig `json:"global"`
 	ContractList []Contract   `json:"contractList"`
@@ -57,43 +82,62 @@ type Contract struct {
 	StepSize     string `json:"stepSize"`
 }
 
-func NewClient(cfg config.EdgeXConfig) *Client {
+// EdgeX doesn't publish limits for the endpoints used here, so every
+// request weighs 1 against a conservative budget.
+const (
+	defaultWeightPerMinute = 300
+	weightRequest          = 1
+)
+
+// Market data is reused for this long, so the lookups of one strategy tick
+// share a single request
+const snapshotTTL = 500 * time.Millisecond
+
+// NewLimiter creates the limiter shared by all EdgeX accounts.
+func NewLimiter(cfg config.EdgeXConfig) *exchange.Limiter {
+	weight := cfg.RateLimitPerMinute
+	if weight == 0 {
+		weight = defaultWeightPerMinute
+	}
+	return exchange.NewLimiter("edgex", weight)
+}
+
+// Account calls need the SDK client; NewClient warns once if it couldn't be
+// created
+var errNoAccount = fmt.Errorf("%w: SDK client not initialized (check account_id and stark_private_key)", exchange.ErrNotImplemented)
+
+// NewClient creates a client. limiter and market may be nil.
+func NewClient(cfg config.EdgeXConfig, limiter *exchange.Limiter, market *marketdata.Cache) *Client {
 	client := &Client{
-		cfg: cfg,
+		cfg:     cfg,
+		limiter: limiter,
+		market:  market,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 	}
 
-	// Initialize SDK client if credentials are configured
-	if cfg.APIKey != "" && cfg.SecretKey != "" {
-		// Parse account ID from API key or config
-		// For now, we'll need to add account_id to config
-		// accountID, _ := strconv.ParseInt(cfg.AccountID, 10, 64)
-
-		// TODO: Initialize EdgeX SDK client
-		// sdkClient, err := edgexsdk.NewClient(
-		// 	edgexsdk.WithBaseURL(cfg.BaseURL),
-		// 	edgexsdk.WithAccountID(accountID),
-		// 	edgexsdk.WithStarkPrivateKey(cfg.SecretKey),
-		// )
-		// if err != nil {
-		// 	fmt.Printf("Warning: Failed to create EdgeX SDK client: %v\n", err)
-		// } else {
-		// 	client.sdkClient = sdkClient
-		// 	fmt.Println("EdgeX SDK client initialized successfully")
-		// }
-	}
-
-	// Fetch metadata on initialization
-	ctx := context.Background()
-	for i := 0; i < 3; i++ {
-		if err := client.fetchMetadata(ctx); err != nil {
-			fmt.Printf("Warning: Failed to fetch EdgeX metadata (attempt %d/3): %v\n", i+1, err)
-			time.Sleep(time.Second)
-			continue
+	// The SDK signs account requests with the Stark key
+	if cfg.AccountID == "" || cfg.StarkPrivateKey == "" {
+		log.Warn("account_id or stark_private_key not set, account data and funding payments unavailable")
+	} else if accountID, err := strconv.ParseInt(cfg.AccountID, 10, 64); err != nil {
+		log.Warn("invalid account_id, account data and funding payments unavailable", logger.Err(err))
+	} else {
+		sdkClient, err := edgexsdk.NewClient(&edgexsdk.ClientConfig{
+			BaseURL:     cfg.BaseURL,
+			AccountID:   accountID,
+			StarkPriKey: string(cfg.StarkPrivateKey),
+		})
+		if err != nil {
+			log.Warn("failed to create SDK client, account data and funding payments unavailable", logger.Err(err))
+		} else {
+			client.sdkClient = sdkClient
 		}
-		break
+	}
+
+	// Fetch metadata on initialization (retried inside if transient)
+	if err := client.fetchMetadata(context.Background()); err != nil {
+		log.Warn("failed to fetch metadata", logger.Err(err))
 	}
 
 	return client
@@ -104,42 +148,45 @@ var _ exchange.Exchange = (*Client)(nil)
 func (c *Client) fetchMetadata(ctx context.Context) error {
 	url := c.cfg.BaseURL + "/api/v1/public/meta/getMetaData"
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	data, err := c.getPublic(ctx, url)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	var metadata MetadataResponse
+	if err := json.Unmarshal(data, &metadata); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
+	c.mu.Lock()
+	c.metadata = &metadata
+	c.mu.Unlock()
+	return nil
+}
 
-	var apiResp EdgeXResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return err
-	}
+func (c *Client) meta() *MetadataResponse {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.metadata
+}
 
-	if apiResp.Code != "SUCCESS" {
-		return fmt.Errorf("API error: %s", apiResp.Code)
+// CheckReady retries fetching contract metadata if it failed at startup,
+// then checks the API still answers by asking for the server time.
+func (c *Client) CheckReady() error {
+	if c.meta() == nil {
+		if err := c.fetchMetadata(context.Background()); err != nil {
+			return fmt.Errorf("failed to fetch metadata: %w", err)
+		}
 	}
-
-	var metadata MetadataResponse
-	if err := json.Unmarshal(apiResp.Data, &metadata); err != nil {
-		return err
+	if _, err := c.getPublic(context.Background(), c.cfg.BaseURL+"/api/v1/public/meta/getServerTime"); err != nil {
+		return fmt.Errorf("failed to fetch server time: %w", err)
 	}
-
-	c.metadata = &metadata
 	return nil
 }
 
 func (c *Client) getContractId(symbol string) (string, error) {
-	if c.metadata == nil {
+	metadata := c.meta()
+	if metadata == nil {
 		return "", fmt.Errorf("metadata not loaded")
 	}
 
@@ -149,13 +196,29 @@ func (c *Client) getContractId(symbol string) (string, error) {
 	// EdgeX uses USD not USDT
 	normalizedSymbol = strings.TrimSuffix(normalizedSymbol, "T") // Remove trailing T if present
 
-	for _, contract := range c.metadata.ContractList {
+	for _, contract := range metadata.ContractList {
 		if contract.ContractName == normalizedSymbol {
 			return contract.ContractId, nil
 		}
 	}
 
-	return "", fmt.Errorf("contract not found for symbol: %s (normalized: %s)", symbol, normalizedSymbol)
+	return "", fmt.Errorf("%w: contract for symbol %s (normalized: %s)", exchange.ErrNotFound, symbol, normalizedSymbol)
+}
+
+// getSymbol converts a contract ID back to a pair symbol: ETHUSD -> ETH-USD
+func (c *Client) getSymbol(contractId string) (string, error) {
+	metadata := c.meta()
+	if metadata == nil {
+		return "", fmt.Errorf("metadata not loaded")
+	}
+
+	for _, contract := range metadata.ContractList {
+		if contract.ContractId == contractId {
+			return strings.TrimSuffix(contract.ContractName, "USD") + "-USD", nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: symbol for contract %s", exchange.ErrNotFound, contractId)
 }
 
 // addAuthHeaders adds authentication headers to the request if API key is configured
@@ -163,8 +226,8 @@ func (c *Client) addAuthHeaders(req *http.Request) {
 	if c.cfg.APIKey != "" && c.cfg.SecretKey != "" {
 		// EdgeX uses specific auth headers
 		// Adjust based on actual EdgeX API documentation
-		req.Header.Set("X-API-KEY", c.cfg.APIKey)
-		req.Header.Set("X-API-SECRET", c.cfg.SecretKey)
+		req.Header.Set("X-API-KEY", string(c.cfg.APIKey))
+		req.Header.Set("X-API-SECRET", string(c.cfg.SecretKey))
 		// Or use Authorization header:
 		// req.Header.Set("Authorization", "Bearer " + c.cfg.APIKey)
 	}
@@ -184,111 +247,345 @@ func (c *Client) makeAuthenticatedRequest(method, url string, body io.Reader) (*
 }
 
 func (c *Client) GetFundingRate(symbol string) (float64, error) {
-	contractId, err := c.getContractId(symbol)
+	rates, err := c.GetFundingRates([]string{symbol})
 	if err != nil {
 		return 0, err
 	}
+	rate, ok := rates[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: funding rate for %s", exchange.ErrNotFound, symbol)
+	}
+	return rate, nil
+}
 
-	url := fmt.Sprintf("%s/api/v1/public/funding/getLatestFundingRate?contractId=%s",
-		c.cfg.BaseURL, contractId)
-
-	resp, err := c.httpClient.Get(url)
+func (c *Client) GetPrice(symbol string) (float64, error) {
+	prices, err := c.GetPrices([]string{symbol})
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
+	price, ok := prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: price for %s", exchange.ErrNotFound, symbol)
+	}
+	return price, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+func (c *Client) GetFundingRates(symbols []string) (map[string]float64, error) {
+	rates, missing := c.market.FundingRates(exchangeName, symbols)
+	return c.fillFunding(rates, missing, func(d FundingRateData) string { return d.FundingRate })
+}
+
+// GetPrices returns last traded prices: like the other venues' mid prices,
+// and unlike the index price, a price the book actually trades at. Symbols
+// the WebSocket ticker has no fresh price for are fetched over REST.
+func (c *Client) GetPrices(symbols []string) (map[string]float64, error) {
+	prices, missing := c.market.Prices(exchangeName, symbols)
+	for _, symbol := range missing {
+		contractId, err := c.getContractId(symbol)
+		if err != nil {
+			return nil, err
+		}
+		price, ok, err := c.lastPrice(contractId)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			prices[symbol] = price
+		}
+	}
+	return prices, nil
+}
+
+// lastPrice calls getTicker for one contract, reporting false if it has no
+// trades yet.
+func (c *Client) lastPrice(contractId string) (float64, bool, error) {
+	data, err := c.getPublic(context.Background(), c.cfg.BaseURL+"/api/v1/public/quote/getTicker?contractId="+contractId)
 	if err != nil {
-		return 0, err
+		return 0, false, err
 	}
 
-	var apiResp EdgeXResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return 0, err
+	var tickers []TickerData
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		return 0, false, err
 	}
+	for _, t := range tickers {
+		if t.ContractId != contractId || t.LastPrice == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(t.LastPrice, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid last price for contract %s: %w", contractId, err)
+		}
+		return price, price > 0, nil
+	}
+	return 0, false, nil
+}
 
-	if apiResp.Code != "SUCCESS" {
-		return 0, fmt.Errorf("API error: %s", apiResp.Code)
+// fillFunding adds the values of the symbols the WebSocket feed had no
+// fresh data for, fetched over REST.
+func (c *Client) fillFunding(out map[string]float64, missing []string, field func(FundingRateData) string) (map[string]float64, error) {
+	if len(missing) == 0 {
+		return out, nil
 	}
+	fetched, err := c.fundingField(missing, field)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(out, fetched)
+	return out, nil
+}
 
-	var fundingData []FundingRateData
-	if err := json.Unmarshal(apiResp.Data, &fundingData); err != nil {
-		return 0, err
+func (c *Client) fundingField(symbols []string, field func(FundingRateData) string) (map[string]float64, error) {
+	contractIds := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		contractId, err := c.getContractId(symbol)
+		if err != nil {
+			return nil, err
+		}
+		contractIds[symbol] = contractId
 	}
 
-	if len(fundingData) == 0 {
-		return 0, fmt.Errorf("no funding data returned")
+	data, err := c.latestFunding(slices.Collect(maps.Values(contractIds)))
+	if err != nil {
+		return nil, err
 	}
 
-	return strconv.ParseFloat(fundingData[0].FundingRate, 64)
+	out := make(map[string]float64, len(symbols))
+	for symbol, contractId := range contractIds {
+		d, ok := data[contractId]
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(field(d), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse funding data for %s: %w", symbol, err)
+		}
+		out[symbol] = value
+	}
+	return out, nil
 }
 
-func (c *Client) GetPrice(symbol string) (float64, error) {
-	contractId, err := c.getContractId(symbol)
-	if err != nil {
-		return 0, err
+// latestFunding returns the latest funding data of the given contracts,
+// reusing the last response if it is younger than snapshotTTL. Without a
+// contractId the endpoint lists every contract; any it leaves out are
+// fetched one by one.
+func (c *Client) latestFunding(contractIds []string) (map[string]FundingRateData, error) {
+	c.snapMu.Lock()
+	defer c.snapMu.Unlock()
+
+	if c.snapshot == nil || time.Since(c.snapshotAt) >= snapshotTTL {
+		all, err := c.fetchFunding("")
+		if err != nil {
+			return nil, err
+		}
+		c.snapshot = make(map[string]FundingRateData, len(all))
+		for _, d := range all {
+			c.snapshot[d.ContractId] = d
+		}
+		c.snapshotAt = time.Now()
 	}
 
-	// Use funding rate endpoint to get index price
-	url := fmt.Sprintf("%s/api/v1/public/funding/getLatestFundingRate?contractId=%s",
-		c.cfg.BaseURL, contractId)
-
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return 0, err
+	for _, contractId := range contractIds {
+		if _, ok := c.snapshot[contractId]; ok {
+			continue
+		}
+		data, err := c.fetchFunding(contractId)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range data {
+			c.snapshot[d.ContractId] = d
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
+	out := make(map[string]FundingRateData, len(contractIds))
+	for _, contractId := range contractIds {
+		if d, ok := c.snapshot[contractId]; ok {
+			out[contractId] = d
+		}
 	}
+	return out, nil
+}
 
-	var apiResp EdgeXResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return 0, err
+// fetchFunding calls getLatestFundingRate, for all contracts if contractId
+// is empty.
+func (c *Client) fetchFunding(contractId string) ([]FundingRateData, error) {
+	url := c.cfg.BaseURL + "/api/v1/public/funding/getLatestFundingRate"
+	if contractId != "" {
+		url += "?contractId=" + contractId
 	}
 
-	if apiResp.Code != "SUCCESS" {
-		return 0, fmt.Errorf("API error: %s", apiResp.Code)
+	data, err := c.getPublic(context.Background(), url)
+	if err != nil {
+		return nil, err
 	}
 
 	var fundingData []FundingRateData
-	if err := json.Unmarshal(apiResp.Data, &fundingData); err != nil {
-		return 0, err
+	if err := json.Unmarshal(data, &fundingData); err != nil {
+		return nil, err
 	}
+	return fundingData, nil
+}
 
-	if len(fundingData) == 0 {
-		return 0, fmt.Errorf("no funding data returned")
-	}
+// getPublic GETs a public endpoint and returns the data of a successful
+// response, retrying failures that may go away.
+func (c *Client) getPublic(ctx context.Context, url string) (json.RawMessage, error) {
+	return exchange.Retry(url, func() (json.RawMessage, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.limiter.Wait(exchange.PriorityData, weightRequest); err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, exchange.ClassifyNetwork(err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, exchange.ClassifyNetwork(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, exchange.ClassifyStatus(resp.StatusCode,
+				fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body)))
+		}
+
+		var apiResp EdgeXResponse
+		if err := json.Unmarshal(body, &apiResp); err != nil {
+			return nil, err
+		}
 
-	return strconv.ParseFloat(fundingData[0].IndexPrice, 64)
+		if apiResp.Code != "SUCCESS" {
+			return nil, fmt.Errorf("API error: %s", apiResp.Code)
+		}
+		return apiResp.Data, nil
+	})
+}
+
+// GetOrderBook returns the book kept by the WebSocket feed; there is no
+// REST fallback.
+func (c *Client) GetOrderBook(symbol string) (*marketdata.Book, error) {
+	book, ok := c.market.Book(exchangeName, symbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: no fresh order book for %s from the WebSocket feed", exchange.ErrNotFound, symbol)
+	}
+	return &book, nil
 }
 
 func (c *Client) GetBalance(asset string) (float64, error) {
 	if c.sdkClient == nil {
-		return 0, fmt.Errorf("SDK client not initialized - requires authentication")
+		return 0, errNoAccount
 	}
 
 	// TODO: Use SDK to get balance
 	// assets, err := c.sdkClient.Asset.GetAccountAsset(context.Background())
-	return 0, fmt.Errorf("not implemented - requires SDK integration")
+	return 0, fmt.Errorf("%w - requires SDK integration", exchange.ErrNotImplemented)
 }
 
 func (c *Client) GetPosition(symbol string) (*exchange.Position, error) {
 	if c.sdkClient == nil {
-		return nil, fmt.Errorf("SDK client not initialized - requires authentication")
+		return nil, errNoAccount
 	}
 
 	// TODO: Use SDK to get position
 	// positions, err := c.sdkClient.Account.GetAccountPosition(context.Background())
-	return nil, fmt.Errorf("not implemented - requires SDK integration")
+	return nil, fmt.Errorf("%w - requires SDK integration", exchange.ErrNotImplemented)
+}
+
+func (c *Client) GetPositions() ([]*exchange.Position, error) {
+	if c.sdkClient == nil {
+		return nil, errNoAccount
+	}
+
+	// TODO: Use SDK to list positions
+	return nil, fmt.Errorf("%w - requires SDK integration", exchange.ErrNotImplemented)
+}
+
+func (c *Client) GetOpenOrders() ([]*exchange.Order, error) {
+	if c.sdkClient == nil {
+		return nil, errNoAccount
+	}
+
+	// TODO: Use SDK to list active orders
+	// orders, err := c.sdkClient.Order.GetActiveOrderPage(context.Background(), ...)
+	return nil, fmt.Errorf("%w - requires SDK integration", exchange.ErrNotImplemented)
+}
+
+func (c *Client) GetFundingPayments(since time.Time) ([]*exchange.FundingPayment, error) {
+	if c.sdkClient == nil {
+		return nil, errNoAccount
+	}
+
+	// The start time filter bounds the pages; follow them to the end
+	var txs []account.PositionTransaction
+	offset := ""
+	for {
+		page, err := exchange.Retry("getPositionTransactionPage", func() (*account.PageDataPositionTransactionResponse, error) {
+			if err := c.limiter.Wait(exchange.PriorityData, weightRequest); err != nil {
+				return nil, err
+			}
+			page, err := c.sdkClient.GetPositionTransactionPage(context.Background(), account.GetPositionTransactionPageParams{
+				Size:                   100,
+				OffsetData:             offset,
+				FilterTypeList:         []string{fundingSettleType},
+				FilterStartCreatedTime: since.UnixMilli(),
+			})
+			return page, exchange.ClassifyNetwork(err)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if page.Code != "SUCCESS" {
+			return nil, fmt.Errorf("API error: %s %s", page.Code, page.ErrorMsg)
+		}
+		if page.Data == nil {
+			break
+		}
+		txs = append(txs, page.Data.DataList...)
+		if page.Data.NextPageOffsetData == nil || *page.Data.NextPageOffsetData == "" || len(page.Data.DataList) == 0 {
+			break
+		}
+		offset = *page.Data.NextPageOffsetData
+	}
+
+	var payments []*exchange.FundingPayment
+	for _, tx := range txs {
+		if tx.Id == nil || tx.ContractId == nil {
+			continue
+		}
+		symbol, err := c.getSymbol(*tx.ContractId)
+		if err != nil {
+			return nil, err
+		}
+
+		payment := &exchange.FundingPayment{
+			ID:     *tx.Id,
+			Symbol: symbol,
+		}
+		if tx.Fee != nil {
+			// The settled funding fee is what the account paid
+			fee, _ := strconv.ParseFloat(*tx.Fee, 64)
+			payment.Amount = -fee
+		}
+		if tx.Size != nil {
+			payment.Size, _ = strconv.ParseFloat(*tx.Size, 64)
+		}
+		if tx.CreatedTime != nil {
+			ms, _ := strconv.ParseInt(*tx.CreatedTime, 10, 64)
+			payment.Time = time.UnixMilli(ms)
+		}
+		payments = append(payments, payment)
+	}
+	return payments, nil
 }
 
 func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse, error) {
 	if c.sdkClient == nil {
-		return nil, fmt.Errorf("SDK client not initialized - check api_key and secret_key configuration")
+		return nil, errNoAccount
 	}
 
 	// TODO: Implement using EdgeX SDK
@@ -298,15 +595,15 @@ func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse
 	// 2. Convert order parameters to SDK format
 	// 3. Call SDK's CreateOrder method
 
-	return nil, fmt.Errorf("EdgeX下单功能需要配置 account_id 和 stark_private_key,详见文档")
+	return nil, fmt.Errorf("%w: EdgeX下单功能需要配置 account_id 和 stark_private_key,详见文档", exchange.ErrNotImplemented)
 }
 
 func (c *Client) CancelOrder(symbol, orderID string) error {
 	if c.sdkClient == nil {
-		return fmt.Errorf("SDK client not initialized")
+		return errNoAccount
 	}
 
 	// TODO: Use SDK to cancel order
 	// err := c.sdkClient.Order.CancelOrder(context.Background(), orderID)
-	return fmt.Errorf("not implemented - requires SDK integration")
+	return fmt.Errorf("%w - requires SDK integration", exchange.ErrNotImplemented)
 }
@@ -0,0 +1,419 @@
+package edgex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/events"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
+	"arbitrage-bot/pkg/ws"
+)
+
+// Key of EdgeX data in the market data cache, shared by all accounts
+const exchangeName = "edgex"
+
+// Order book levels subscribed per contract (EdgeX offers 15 or 200)
+const depthLevels = 15
+
+// How often contract metadata is retried while it can't be loaded
+const metadataRetryInterval = 30 * time.Second
+
+// Feed streams EdgeX tickers and order books over WebSocket into the market
+// data cache, which Client reads before falling back to REST, and publishes
+// funding and book updates on the event bus.
+type Feed struct {
+	public *Client // contract metadata only
+	cache  *marketdata.Cache
+	bus    *events.Bus
+	ws     *ws.EdgeXWSClient
+
+	mu         sync.Mutex
+	symbols    map[string]bool       // pairs to stream
+	subscribed map[string]string     // contract ID -> pair
+	books      map[string]*depthBook // by contract ID
+}
+
+// Parsed events of a contract
+type tickerUpdate struct {
+	symbol     string
+	lastPrice  float64
+	indexPrice float64
+	markPrice  float64
+}
+
+type fundingUpdate struct {
+	symbol      string
+	rate        float64
+	nextFunding time.Time
+}
+
+type depthUpdate struct {
+	symbol   string
+	snapshot bool // replaces the book rather than changing it
+	bids     []marketdata.Level
+	asks     []marketdata.Level
+
+	// Book versions the update covers, 0 if not given
+	startVersion int64
+	endVersion   int64
+}
+
+// depthBook is the local order book of a contract, size by price.
+type depthBook struct {
+	bids    map[float64]float64
+	asks    map[float64]float64
+	version int64 // end version of the last update applied
+}
+
+// NewFeed creates a feed for cfg.WSURL; call Track and Start.
+func NewFeed(cfg config.EdgeXConfig, cache *marketdata.Cache, bus *events.Bus) *Feed {
+	f := &Feed{
+		public: &Client{
+			cfg:        cfg,
+			httpClient: &http.Client{Timeout: 10 * time.Second},
+		},
+		cache:      cache,
+		bus:        bus,
+		ws:         ws.NewEdgeXWSClient(cfg.WSURL),
+		symbols:    make(map[string]bool),
+		subscribed: make(map[string]string),
+		books:      make(map[string]*depthBook),
+	}
+	f.ws.OnStateChange = func(state ws.State) {
+		if state != ws.StateConnected {
+			// Books start over from the snapshot sent on resubscription
+			f.reset()
+		}
+	}
+	return f
+}
+
+// Track adds pairs to stream. Pairs EdgeX doesn't list are skipped.
+func (f *Feed) Track(symbols []string) {
+	f.mu.Lock()
+	for _, symbol := range symbols {
+		f.symbols[symbol] = true
+	}
+	f.mu.Unlock()
+
+	f.subscribe()
+}
+
+// Start streams until ctx is cancelled.
+func (f *Feed) Start(ctx context.Context) {
+	if err := f.ws.Connect(ctx); err != nil {
+		log.Error("failed to start websocket feed", logger.Err(err))
+		return
+	}
+	defer f.ws.Close()
+
+	// Channels are named by contract ID, which needs the metadata
+	for {
+		err := f.public.CheckReady()
+		if err == nil {
+			break
+		}
+		log.Warn("websocket feed waiting for metadata", logger.Err(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(metadataRetryInterval):
+		}
+	}
+	f.subscribe()
+
+	<-ctx.Done()
+}
+
+// subscribe subscribes the tracked pairs not subscribed yet, once the
+// metadata is loaded.
+func (f *Feed) subscribe() {
+	if f.public.meta() == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for symbol := range f.symbols {
+		contractId, err := f.public.getContractId(symbol)
+		if err != nil {
+			log.Warn("pair not streamed", logger.KeySymbol, symbol, logger.Err(err))
+			delete(f.symbols, symbol)
+			continue
+		}
+		if _, ok := f.subscribed[contractId]; ok {
+			continue
+		}
+		f.subscribed[contractId] = symbol
+
+		if err := f.ws.Subscribe("ticker."+contractId, f.onTicker); err != nil {
+			log.Warn("failed to subscribe", logger.KeySymbol, symbol, "channel", "ticker", logger.Err(err))
+		}
+		if err := f.ws.Subscribe(depthChannel(contractId), f.onDepth); err != nil {
+			log.Warn("failed to subscribe", logger.KeySymbol, symbol, "channel", "depth", logger.Err(err))
+		}
+	}
+}
+
+func (f *Feed) symbol(contractId string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	symbol, ok := f.subscribed[contractId]
+	return symbol, ok
+}
+
+func (f *Feed) reset() {
+	f.mu.Lock()
+	clear(f.books)
+	f.mu.Unlock()
+
+	f.cache.Clear(exchangeName)
+}
+
+func (f *Feed) onTicker(data json.RawMessage) {
+	var content ws.EdgeXTickerContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		log.Warn("invalid ticker event", logger.Err(err))
+		return
+	}
+
+	now := time.Now()
+	for _, t := range content.Data {
+		symbol, ok := f.symbol(t.ContractId)
+		if !ok {
+			continue
+		}
+		ticker, funding, err := parseTicker(symbol, t)
+		if err != nil {
+			log.Warn("invalid ticker event", logger.KeySymbol, symbol, logger.Err(err))
+			continue
+		}
+
+		// Last price, as GetPrice returns over REST
+		if ticker.lastPrice > 0 {
+			f.cache.SetPrice(exchangeName, symbol, ticker.lastPrice, now)
+		}
+		if t.FundingRate != "" {
+			f.cache.SetFundingRate(exchangeName, symbol, funding.rate, now)
+			f.bus.Publish(events.FundingUpdate{Exchange: exchangeName, Symbol: symbol, Rate: funding.rate, Time: now})
+		}
+		log.Debug("ticker", logger.KeySymbol, symbol, "last_price", ticker.lastPrice, "index_price", ticker.indexPrice,
+			"funding_rate", funding.rate, "next_funding", funding.nextFunding)
+	}
+}
+
+func (f *Feed) onDepth(data json.RawMessage) {
+	var content ws.EdgeXDepthContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		log.Warn("invalid depth event", logger.Err(err))
+		return
+	}
+
+	for _, d := range content.Data {
+		symbol, ok := f.symbol(d.ContractId)
+		if !ok {
+			continue
+		}
+		update, err := parseDepth(symbol, content.DataType, d)
+		if err != nil {
+			log.Warn("invalid depth event", logger.KeySymbol, symbol, logger.Err(err))
+			continue
+		}
+		book, ok, err := f.applyDepth(d.ContractId, update)
+		if err != nil {
+			log.Warn("order book out of sequence, resyncing", logger.KeySymbol, symbol, logger.Err(err))
+			f.resync(d.ContractId)
+			continue
+		}
+		if ok {
+			f.cache.SetBook(exchangeName, symbol, book)
+			f.bus.Publish(events.BookUpdate{Exchange: exchangeName, Symbol: symbol, Book: book})
+		}
+	}
+}
+
+// applyDepth updates the local book and returns its top levels. Changes
+// before the first snapshot are dropped. A change that doesn't follow the
+// last one drops the book and returns an error; call resync.
+func (f *Feed) applyDepth(contractId string, u depthUpdate) (marketdata.Book, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	book := f.books[contractId]
+	switch {
+	case u.snapshot:
+		book = &depthBook{bids: make(map[float64]float64), asks: make(map[float64]float64)}
+		f.books[contractId] = book
+	case book == nil:
+		// Waiting for the snapshot after a resync
+		return marketdata.Book{}, false, nil
+	case !book.follows(u.startVersion, u.endVersion):
+		delete(f.books, contractId)
+		return marketdata.Book{}, false, fmt.Errorf("versions %d-%d after %d", u.startVersion, u.endVersion, book.version)
+	}
+	if u.endVersion != 0 {
+		book.version = u.endVersion
+	}
+
+	for _, side := range []struct {
+		levels map[float64]float64
+		update []marketdata.Level
+	}{{book.bids, u.bids}, {book.asks, u.asks}} {
+		for _, l := range side.update {
+			if l.Size == 0 {
+				delete(side.levels, l.Price)
+			} else {
+				side.levels[l.Price] = l.Size
+			}
+		}
+	}
+
+	return marketdata.Book{
+		Bids: topLevels(book.bids, true),
+		Asks: topLevels(book.asks, false),
+		At:   time.Now(),
+	}, true, nil
+}
+
+// resync resubscribes the depth channel of a contract, which sends a new
+// snapshot.
+func (f *Feed) resync(contractId string) {
+	channel := depthChannel(contractId)
+	if err := f.ws.Unsubscribe(channel); err != nil {
+		log.Warn("failed to unsubscribe", "channel", channel, logger.Err(err))
+	}
+	if err := f.ws.Subscribe(channel, f.onDepth); err != nil {
+		log.Warn("failed to subscribe", "channel", channel, logger.Err(err))
+	}
+}
+
+func depthChannel(contractId string) string {
+	return fmt.Sprintf("depth.%s.%d", contractId, depthLevels)
+}
+
+// follows reports whether a change covering versions start to end continues
+// from the last update applied to the book. Levels carry absolute sizes, so
+// a change overlapping the book is fine; versions are only compared when
+// both are known.
+func (b *depthBook) follows(start, end int64) bool {
+	if start == 0 || b.version == 0 {
+		return true
+	}
+	return start <= b.version+1 && end > b.version
+}
+
+// topLevels returns up to depthLevels levels, best first.
+func topLevels(levels map[float64]float64, descending bool) []marketdata.Level {
+	out := make([]marketdata.Level, 0, len(levels))
+	for price, size := range levels {
+		out = append(out, marketdata.Level{Price: price, Size: size})
+	}
+	slices.SortFunc(out, func(a, b marketdata.Level) int {
+		if descending {
+			a, b = b, a
+		}
+		switch {
+		case a.Price < b.Price:
+			return -1
+		case a.Price > b.Price:
+			return 1
+		}
+		return 0
+	})
+	if len(out) > depthLevels {
+		out = out[:depthLevels]
+	}
+	return out
+}
+
+func parseTicker(symbol string, t ws.EdgeXTicker) (tickerUpdate, fundingUpdate, error) {
+	ticker := tickerUpdate{symbol: symbol}
+	funding := fundingUpdate{symbol: symbol}
+
+	for _, field := range []struct {
+		name  string
+		value string
+		dst   *float64
+	}{
+		{"lastPrice", t.LastPrice, &ticker.lastPrice},
+		{"indexPrice", t.IndexPrice, &ticker.indexPrice},
+		{"markPrice", t.MarkPrice, &ticker.markPrice},
+		{"fundingRate", t.FundingRate, &funding.rate},
+	} {
+		// Fields missing from an event are left at 0
+		if field.value == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(field.value, 64)
+		if err != nil {
+			return ticker, funding, fmt.Errorf("%s: %w", field.name, err)
+		}
+		*field.dst = v
+	}
+
+	if t.NextFundingTime != "" {
+		ms, err := strconv.ParseInt(t.NextFundingTime, 10, 64)
+		if err != nil {
+			return ticker, funding, fmt.Errorf("nextFundingTime: %w", err)
+		}
+		funding.nextFunding = time.UnixMilli(ms)
+	}
+	return ticker, funding, nil
+}
+
+func parseDepth(symbol, dataType string, d ws.EdgeXDepth) (depthUpdate, error) {
+	update := depthUpdate{
+		symbol:   symbol,
+		snapshot: strings.EqualFold(dataType, "snapshot"),
+	}
+
+	for _, v := range []struct {
+		name  string
+		value string
+		dst   *int64
+	}{{"startVersion", d.StartVersion, &update.startVersion}, {"endVersion", d.EndVersion, &update.endVersion}} {
+		if v.value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v.value, 10, 64)
+		if err != nil {
+			return update, fmt.Errorf("%s: %w", v.name, err)
+		}
+		*v.dst = n
+	}
+
+	var err error
+	if update.bids, err = parseLevels(d.Bids); err != nil {
+		return update, fmt.Errorf("bids: %w", err)
+	}
+	if update.asks, err = parseLevels(d.Asks); err != nil {
+		return update, fmt.Errorf("asks: %w", err)
+	}
+	return update, nil
+}
+
+func parseLevels(levels []ws.EdgeXLevel) ([]marketdata.Level, error) {
+	out := make([]marketdata.Level, 0, len(levels))
+	for _, l := range levels {
+		price, err := strconv.ParseFloat(l.Price, 64)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseFloat(l.Size, 64)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, marketdata.Level{Price: price, Size: size})
+	}
+	return out, nil
+}
@@ -0,0 +1,27 @@
+package edgex
+
+import "testing"
+
+func TestDepthBookFollows(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    int64 // of the last update applied
+		start, end int64
+		want       bool
+	}{
+		{"next version", 100, 101, 105, true},
+		{"overlapping change", 100, 98, 103, true},
+		{"version gap", 100, 103, 105, false},
+		{"old change", 100, 95, 100, false},
+		{"change without versions", 100, 0, 0, true},
+		{"snapshot without version", 0, 101, 105, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &depthBook{version: tt.version}
+			if got := b.follows(tt.start, tt.end); got != tt.want {
+				t.Errorf("follows(%d, %d) after version %d = %v, want %v", tt.start, tt.end, tt.version, got, tt.want)
+			}
+		})
+	}
+}
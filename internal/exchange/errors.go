@@ -0,0 +1,111 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	"arbitrage-bot/internal/logger"
+)
+
+// Error classes. Adapters wrap venue errors so callers can use errors.Is.
+var (
+	ErrRateLimited        = errors.New("rate limited")
+	ErrInsufficientMargin = errors.New("insufficient margin")
+	ErrInvalidOrder       = errors.New("invalid order")
+	ErrNotFound           = errors.New("not found")
+	ErrTransient          = errors.New("transient error") // 5xx, timeouts, dropped connections
+	ErrNotImplemented     = errors.New("not implemented")
+)
+
+var classes = []struct {
+	err  error
+	name string
+}{
+	{ErrRateLimited, "rate_limited"},
+	{ErrInsufficientMargin, "insufficient_margin"},
+	{ErrInvalidOrder, "invalid_order"},
+	{ErrNotFound, "not_found"},
+	{ErrTransient, "transient"},
+	{ErrNotImplemented, "not_implemented"},
+	{ErrCircuitOpen, "circuit_open"},
+}
+
+// Class names the class of err for logs and alerts, "unknown" if it has none.
+func Class(err error) string {
+	for _, c := range classes {
+		if errors.Is(err, c.err) {
+			return c.name
+		}
+	}
+	return "unknown"
+}
+
+// Classify wraps err with class, unless it already has one.
+func Classify(class, err error) error {
+	if err == nil || Class(err) != "unknown" {
+		return err
+	}
+	return fmt.Errorf("%w: %w", class, err)
+}
+
+// ClassifyStatus classifies err by the HTTP status of the failed response.
+// 4xx other than 404 and 429 are left for the adapter to classify.
+func ClassifyStatus(status int, err error) error {
+	switch {
+	case status == 429:
+		return Classify(ErrRateLimited, err)
+	case status >= 500:
+		return Classify(ErrTransient, err)
+	case status == 404:
+		return Classify(ErrNotFound, err)
+	}
+	return err
+}
+
+// ClassifyNetwork marks transport failures (timeouts, refused or reset
+// connections) as transient.
+func ClassifyNetwork(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Classify(ErrTransient, err)
+	}
+	return err
+}
+
+// Retryable reports whether the call that returned err may succeed if
+// repeated. Rejections by our own Limiter are not, as it already waited.
+func Retryable(err error) bool {
+	var budget *budgetError
+	if errors.As(err, &budget) {
+		return false
+	}
+	return errors.Is(err, ErrTransient) || errors.Is(err, ErrRateLimited)
+}
+
+const (
+	retryAttempts = 3
+	retryBaseWait = 200 * time.Millisecond
+	retryMaxWait  = 2 * time.Second
+)
+
+// Retry calls fn until it succeeds or fails with a non-retryable error, up to
+// retryAttempts times, sleeping with jittered exponential backoff in between.
+// Only use it for calls that are safe to repeat (not order placement).
+func Retry[T any](op string, fn func() (T, error)) (T, error) {
+	wait := retryBaseWait
+	for attempt := 1; ; attempt++ {
+		v, err := fn()
+		if err == nil || attempt == retryAttempts || !Retryable(err) {
+			return v, err
+		}
+
+		// Full jitter, so venues' clients don't retry in lockstep
+		sleep := time.Duration(rand.Int63n(int64(wait)))
+		log.Debug("retrying request", "op", op, "attempt", attempt, "wait", sleep, logger.Err(err))
+		time.Sleep(sleep)
+		wait = min(2*wait, retryMaxWait)
+	}
+}
@@ -1,134 +1,577 @@
 package hyperliquid
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"arbitrage-bot/internal/config"
 	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/sonirico/go-hyperliquid"
 )
 
+var log = logger.For("hyperliquid")
+
 type Client struct {
-	cfg      config.HyperliquidConfig
-	info     *hyperliquid.Info
+	cfg        config.HyperliquidConfig
+	httpClient *http.Client
+	info       *hyperliquid.Info
+	privateKey *ecdsa.PrivateKey
+	address    string
+	limiter    *exchange.Limiter
+	user       string            // account queried for orders/positions: the vault if set
+	market     *marketdata.Cache // fed by Feed, nil without one
+
+	// Set once meta has loaded; see loadMeta
+	mu       sync.RWMutex
 	exchange *hyperliquid.Exchange
 	meta     *hyperliquid.Meta
+
+	// Last MetaAndAssetCtxs response by coin; see assetCtxs
+	snapMu     sync.Mutex
+	snapshot   map[string]hyperliquid.AssetCtx
+	snapshotAt time.Time
+
+	// Taker fee rate of the account; see takerFeeRate
+	feeMu     sync.Mutex
+	feeRate   float64
+	feeRateAt time.Time
 }
 
-func NewClient(cfg config.HyperliquidConfig) *Client {
-	ctx := context.Background()
+// UserFunding is one entry of the "userFunding" info response
+type UserFunding struct {
+	Time  int64  `json:"time"`
+	Hash  string `json:"hash"`
+	Delta struct {
+		Type        string `json:"type"`
+		Coin        string `json:"coin"`
+		Usdc        string `json:"usdc"`
+		Szi         string `json:"szi"`
+		FundingRate string `json:"fundingRate"`
+	} `json:"delta"`
+}
+
+// Hyperliquid allows 1200 weight per minute per IP. Most info requests weigh
+// 20, a few (clearinghouseState, l2Book, allMids) 2, and exchange actions 1.
+const (
+	defaultWeightPerMinute = 1200
+	weightInfo             = 20
+	weightUserState        = 2
+	weightBook             = 2
+	weightMids             = 2
+	weightAction           = 1
+)
 
+// Market data is reused for this long, so the funding and price lookups of
+// one strategy tick share a single request
+const snapshotTTL = 500 * time.Millisecond
+
+// The fee tier only changes with 14-day volume
+const feeRateTTL = time.Hour
+
+// NewLimiter creates the limiter shared by all Hyperliquid accounts.
+func NewLimiter(cfg config.HyperliquidConfig) *exchange.Limiter {
+	weight := cfg.RateLimitPerMinute
+	if weight == 0 {
+		weight = defaultWeightPerMinute
+	}
+	return exchange.NewLimiter("hyperliquid", weight)
+}
+
+// NewClient creates a client. limiter and market may be nil.
+func NewClient(cfg config.HyperliquidConfig, limiter *exchange.Limiter, market *marketdata.Cache) *Client {
 	// Initialize Info client
 	// NewInfo(ctx, baseURL, skipWS, meta, spotMeta, opts...)
-	info := hyperliquid.NewInfo(ctx, cfg.BaseURL, true, nil, nil)
-
-	// Fetch Meta (needed for Exchange and symbol lookup)
-	meta, err := info.Meta(ctx)
-	if err != nil {
-		log.Printf("Failed to fetch meta: %v", err)
+	// NewInfo panics if it has to fetch meta itself and that fails, so it
+	// gets empty metas; the calls we make on it don't need the coin mapping.
+	info := hyperliquid.NewInfo(context.Background(), cfg.BaseURL, true, &hyperliquid.Meta{}, &hyperliquid.SpotMeta{})
+
+	c := &Client{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		info:    info,
+		address: cfg.WalletAddress,
+		limiter: limiter,
+		market:  market,
 	}
 
-	var exc *hyperliquid.Exchange
-	if cfg.PrivateKey != "" && meta != nil {
-		pk, err := crypto.HexToECDSA(cfg.PrivateKey)
+	if cfg.PrivateKey != "" {
+		pk, err := crypto.HexToECDSA(strings.TrimPrefix(string(cfg.PrivateKey), "0x"))
 		if err != nil {
-			log.Printf("Failed to parse private key: %v", err)
+			log.Error("failed to parse private key", logger.Err(err))
 		} else {
+			c.privateKey = pk
 			// Derive address if not provided
-			walletAddr := cfg.WalletAddress
-			if walletAddr == "" {
-				walletAddr = crypto.PubkeyToAddress(pk.PublicKey).Hex()
+			if c.address == "" {
+				c.address = crypto.PubkeyToAddress(pk.PublicKey).Hex()
 			}
+		}
+	}
 
-			// NewExchange(ctx, pk, baseURL, meta, vaultAddress, accountAddress, spotMeta, opts...)
-			exc = hyperliquid.NewExchange(ctx, pk, cfg.BaseURL, meta, "", walletAddr, nil)
+	c.user = c.address
+	if cfg.VaultAddress != "" {
+		c.user = cfg.VaultAddress
+	}
+
+	// Fetch Meta (needed for Exchange and symbol lookup)
+	if err := c.loadMeta(); err != nil {
+		log.Warn("failed to fetch meta", logger.Err(err))
+	}
+
+	return c
+}
+
+// loadMeta fetches the asset universe and, if a private key is configured,
+// creates the trading client that depends on it.
+func (c *Client) loadMeta() error {
+	ctx := context.Background()
+
+	meta, err := query(c, "meta", weightInfo, c.info.Meta)
+	if err != nil {
+		return err
+	}
+
+	var exc *hyperliquid.Exchange
+	if c.privateKey != nil {
+		// Fetched here too, since NewExchange would panic on failure
+		spotMeta, err := query(c, "spotMeta", weightInfo, c.info.SpotMeta)
+		if err != nil {
+			return fmt.Errorf("failed to fetch spot meta: %w", err)
 		}
+		// NewExchange(ctx, pk, baseURL, meta, vaultAddress, accountAddress, spotMeta, opts...)
+		exc = hyperliquid.NewExchange(ctx, c.privateKey, c.cfg.BaseURL, meta, c.cfg.VaultAddress, c.address, spotMeta)
 	}
 
-	return &Client{
-		cfg:      cfg,
-		info:     info,
-		exchange: exc,
-		meta:     meta,
+	c.mu.Lock()
+	c.meta = meta
+	c.exchange = exc
+	c.mu.Unlock()
+	return nil
+}
+
+// trading returns the trading client and meta, either of which may be nil.
+func (c *Client) trading() (*hyperliquid.Exchange, *hyperliquid.Meta) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.exchange, c.meta
+}
+
+// CheckReady retries loading meta if it failed at startup, then checks the
+// API still answers with a light request: the account state if there is
+// one, otherwise the mid prices.
+func (c *Client) CheckReady() error {
+	if c.cfg.PrivateKey != "" && c.privateKey == nil {
+		return fmt.Errorf("invalid private key")
 	}
+	if _, meta := c.trading(); meta == nil {
+		// The SDK error already says "failed to fetch meta"
+		if err := c.loadMeta(); err != nil {
+			return err
+		}
+	}
+	if c.user != "" {
+		if _, err := c.userState(); err != nil {
+			return fmt.Errorf("failed to fetch account state: %w", err)
+		}
+		return nil
+	}
+	if _, err := query(c, "allMids", weightMids, c.info.AllMids); err != nil {
+		return fmt.Errorf("failed to fetch mid prices: %w", err)
+	}
+	return nil
 }
 
 // Implement Exchange interface
 var _ exchange.Exchange = (*Client)(nil)
 
 func (c *Client) GetFundingRate(symbol string) (float64, error) {
-	// Normalize symbol: ETH-USD -> ETH
-	normalizedSymbol := strings.TrimSuffix(symbol, "-USD")
+	rates, err := c.GetFundingRates([]string{symbol})
+	if err != nil {
+		return 0, err
+	}
+	rate, ok := rates[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: symbol %s not in universe", exchange.ErrNotFound, strings.TrimSuffix(symbol, "-USD"))
+	}
+	return rate, nil
+}
 
-	// Use SDK to get MetaAndAssetCtxs
-	state, err := c.info.MetaAndAssetCtxs(context.Background())
+func (c *Client) GetPrice(symbol string) (float64, error) {
+	prices, err := c.GetPrices([]string{symbol})
 	if err != nil {
 		return 0, err
 	}
+	price, ok := prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: symbol %s not in universe", exchange.ErrNotFound, strings.TrimSuffix(symbol, "-USD"))
+	}
+	return price, nil
+}
 
-	// Find the asset index
-	assetIndex := -1
-	for i, asset := range state.Universe {
-		if asset.Name == normalizedSymbol {
-			assetIndex = i
-			break
+// GetFundingRates reads the symbols the WebSocket feed has no fresh rate for
+// from a single MetaAndAssetCtxs request.
+func (c *Client) GetFundingRates(symbols []string) (map[string]float64, error) {
+	rates, missing := c.market.FundingRates(exchangeName, symbols)
+	if len(missing) == 0 {
+		return rates, nil
+	}
+	ctxs, err := c.assetCtxs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, symbol := range missing {
+		// Normalize symbol: ETH-USD -> ETH
+		ctx, ok := ctxs[strings.TrimSuffix(symbol, "-USD")]
+		if !ok {
+			continue
+		}
+		rate, err := strconv.ParseFloat(ctx.Funding, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse funding rate for %s: %w", symbol, err)
 		}
+		rates[symbol] = rate
 	}
+	return rates, nil
+}
 
-	if assetIndex == -1 {
-		return 0, fmt.Errorf("symbol %s not found in universe", normalizedSymbol)
+// GetPrices returns mid prices, from the same request as GetFundingRates
+// where the WebSocket feed has none.
+func (c *Client) GetPrices(symbols []string) (map[string]float64, error) {
+	prices, missing := c.market.Prices(exchangeName, symbols)
+	if len(missing) == 0 {
+		return prices, nil
+	}
+	ctxs, err := c.assetCtxs()
+	if err != nil {
+		return nil, err
 	}
 
-	if assetIndex >= len(state.Ctxs) {
-		return 0, fmt.Errorf("asset context not found for index %d", assetIndex)
+	for _, symbol := range missing {
+		ctx, ok := ctxs[strings.TrimSuffix(symbol, "-USD")]
+		if !ok {
+			continue
+		}
+		price, err := strconv.ParseFloat(ctx.MidPx, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mid price for %s: %w", symbol, err)
+		}
+		prices[symbol] = price
+	}
+	return prices, nil
+}
+
+// assetCtxs returns the asset contexts by coin, reusing the last response
+// if it is younger than snapshotTTL.
+func (c *Client) assetCtxs() (map[string]hyperliquid.AssetCtx, error) {
+	c.snapMu.Lock()
+	defer c.snapMu.Unlock()
+
+	if c.snapshot != nil && time.Since(c.snapshotAt) < snapshotTTL {
+		return c.snapshot, nil
 	}
 
-	fundingStr := state.Ctxs[assetIndex].Funding
-	fundingRate, err := strconv.ParseFloat(fundingStr, 64)
+	state, err := query(c, "metaAndAssetCtxs", weightInfo, c.info.MetaAndAssetCtxs)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse funding rate: %w", err)
+		return nil, err
 	}
 
-	return fundingRate, nil
+	ctxs := make(map[string]hyperliquid.AssetCtx, len(state.Universe))
+	for i, asset := range state.Universe {
+		if i < len(state.Ctxs) {
+			ctxs[asset.Name] = state.Ctxs[i]
+		}
+	}
+	c.snapshot = ctxs
+	c.snapshotAt = time.Now()
+	return ctxs, nil
 }
 
-func (c *Client) GetPrice(symbol string) (float64, error) {
-	// Normalize symbol
-	normalizedSymbol := strings.TrimSuffix(symbol, "-USD")
+// takerFeeRate returns the account's taker fee rate, reusing the last one if
+// it is younger than feeRateTTL.
+func (c *Client) takerFeeRate() (float64, error) {
+	c.feeMu.Lock()
+	defer c.feeMu.Unlock()
 
-	state, err := c.info.MetaAndAssetCtxs(context.Background())
+	if !c.feeRateAt.IsZero() && time.Since(c.feeRateAt) < feeRateTTL {
+		return c.feeRate, nil
+	}
+
+	fees, err := query(c, "userFees", weightInfo, func(ctx context.Context) (*hyperliquid.UserFees, error) {
+		return c.info.UserFees(ctx, c.user)
+	})
 	if err != nil {
 		return 0, err
 	}
+	rate, err := strconv.ParseFloat(fees.UserCrossRate, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid taker fee rate %q: %w", fees.UserCrossRate, err)
+	}
+	c.feeRate = rate
+	c.feeRateAt = time.Now()
+	return rate, nil
+}
 
-	for i, asset := range state.Universe {
-		if asset.Name == normalizedSymbol {
-			if i < len(state.Ctxs) {
-				return strconv.ParseFloat(state.Ctxs[i].MidPx, 64)
-			}
-		}
+// GetOrderBook returns the book from the WebSocket feed while it is fresh,
+// else requests it.
+func (c *Client) GetOrderBook(symbol string) (*marketdata.Book, error) {
+	if book, ok := c.market.Book(exchangeName, symbol); ok {
+		return &book, nil
+	}
+
+	// The SDK's L2Snapshot needs the coin mapping the Info client lacks
+	reqBody, err := json.Marshal(map[string]any{
+		"type": "l2Book",
+		"coin": strings.TrimSuffix(symbol, "-USD"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := query(c, "l2Book", weightBook, func(ctx context.Context) ([]byte, error) {
+		return c.post(ctx, reqBody)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var book wsBook
+	if err := json.Unmarshal(body, &book); err != nil {
+		return nil, fmt.Errorf("failed to parse l2Book response: %w", err)
 	}
-	return 0, fmt.Errorf("symbol not found")
+	if book.Coin == "" {
+		return nil, fmt.Errorf("%w: symbol %s not in universe", exchange.ErrNotFound, strings.TrimSuffix(symbol, "-USD"))
+	}
+	return parseBook(book)
 }
 
 func (c *Client) GetBalance(asset string) (float64, error) {
-	// TODO: Implement using c.info.UserState(address)
-	return 0, fmt.Errorf("not implemented")
+	state, err := c.userState()
+	if err != nil {
+		return 0, err
+	}
+
+	// Perp margin is USDC; the account value is the only balance we track here
+	return strconv.ParseFloat(state.MarginSummary.AccountValue, 64)
 }
 
 func (c *Client) GetPosition(symbol string) (*exchange.Position, error) {
-	// TODO: Implement using c.info.UserState(address)
-	return nil, fmt.Errorf("not implemented")
+	positions, err := c.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pos := range positions {
+		if pos.Symbol == symbol {
+			return pos, nil
+		}
+	}
+	return &exchange.Position{Symbol: symbol}, nil
+}
+
+func (c *Client) GetPositions() ([]*exchange.Position, error) {
+	state, err := c.userState()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]*exchange.Position, 0, len(state.AssetPositions))
+	for _, ap := range state.AssetPositions {
+		size, err := strconv.ParseFloat(ap.Position.Szi, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse size for %s: %w", ap.Position.Coin, err)
+		}
+		if size == 0 {
+			continue
+		}
+
+		pos := &exchange.Position{
+			Symbol: ap.Position.Coin + "-USD",
+			Size:   size,
+		}
+		if ap.Position.EntryPx != nil {
+			pos.EntryPrice, _ = strconv.ParseFloat(*ap.Position.EntryPx, 64)
+		}
+		pos.UnrealizedPnL, _ = strconv.ParseFloat(ap.Position.UnrealizedPnl, 64)
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
+
+func (c *Client) GetOpenOrders() ([]*exchange.Order, error) {
+	if c.user == "" {
+		return nil, fmt.Errorf("wallet address not configured")
+	}
+
+	openOrders, err := query(c, "openOrders", weightInfo, func(ctx context.Context) ([]hyperliquid.OpenOrder, error) {
+		return c.info.OpenOrders(ctx, c.user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*exchange.Order, 0, len(openOrders))
+	for _, o := range openOrders {
+		// Hyperliquid reports side as "B" (bid) or "A" (ask)
+		side := "buy"
+		if o.Side == "A" {
+			side = "sell"
+		}
+		orders = append(orders, &exchange.Order{
+			OrderID: strconv.FormatInt(o.Oid, 10),
+			Symbol:  o.Coin + "-USD",
+			Side:    side,
+			Size:    o.Size,
+			Price:   o.LimitPx,
+		})
+	}
+	return orders, nil
+}
+
+func (c *Client) GetFundingPayments(since time.Time) ([]*exchange.FundingPayment, error) {
+	if c.user == "" {
+		return nil, fmt.Errorf("wallet address not configured")
+	}
+
+	// The SDK's UserFundingHistory response type doesn't match the API, so
+	// query the info endpoint directly
+	reqBody, err := json.Marshal(map[string]any{
+		"type":      "userFunding",
+		"user":      c.user,
+		"startTime": since.UnixMilli(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := query(c, "userFunding", weightInfo, func(ctx context.Context) ([]byte, error) {
+		return c.post(ctx, reqBody)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var fundings []UserFunding
+	if err := json.Unmarshal(body, &fundings); err != nil {
+		return nil, fmt.Errorf("failed to parse userFunding response: %w", err)
+	}
+
+	payments := make([]*exchange.FundingPayment, 0, len(fundings))
+	for _, f := range fundings {
+		if f.Delta.Type != "funding" {
+			continue
+		}
+		payment := &exchange.FundingPayment{
+			// One hash covers every coin settled in the same funding tick
+			ID:     f.Hash + "/" + f.Delta.Coin,
+			Symbol: f.Delta.Coin + "-USD",
+			Time:   time.UnixMilli(f.Time),
+		}
+		payment.Amount, _ = strconv.ParseFloat(f.Delta.Usdc, 64)
+		payment.Rate, _ = strconv.ParseFloat(f.Delta.FundingRate, 64)
+		payment.Size, _ = strconv.ParseFloat(f.Delta.Szi, 64)
+		payments = append(payments, payment)
+	}
+	return payments, nil
+}
+
+func (c *Client) userState() (*hyperliquid.UserState, error) {
+	if c.user == "" {
+		return nil, fmt.Errorf("wallet address not configured")
+	}
+	return query(c, "clearinghouseState", weightUserState, func(ctx context.Context) (*hyperliquid.UserState, error) {
+		return c.info.UserState(ctx, c.user)
+	})
+}
+
+// post sends a raw info request
+func (c *Client) post(ctx context.Context, reqBody []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.cfg.BaseURL+"/info", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// query runs an info request under the rate limit, retrying failures that
+// may go away.
+func query[T any](c *Client, op string, weight int, fn func(ctx context.Context) (T, error)) (T, error) {
+	return exchange.Retry(op, func() (T, error) {
+		if err := c.limiter.Wait(exchange.PriorityData, weight); err != nil {
+			var zero T
+			return zero, err
+		}
+		v, err := fn(context.Background())
+		return v, classify(err)
+	})
+}
+
+// Both the SDK and post report HTTP failures as "... status NNN: body"
+var statusPattern = regexp.MustCompile(`status (\d{3})`)
+
+// classify maps SDK and HTTP errors to exchange error classes.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	err = exchange.ClassifyNetwork(err)
+	if m := statusPattern.FindStringSubmatch(err.Error()); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		err = exchange.ClassifyStatus(status, err)
+	}
+	return err
+}
+
+// classifyOrder also maps Hyperliquid's order and cancel rejections, which
+// come back as plain messages.
+func classifyOrder(err error) error {
+	err = classify(err)
+	if exchange.Class(err) != "unknown" {
+		return err
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "insufficient margin"):
+		return exchange.Classify(exchange.ErrInsufficientMargin, err)
+	case strings.Contains(msg, "never placed"), strings.Contains(msg, "already canceled"):
+		return exchange.Classify(exchange.ErrNotFound, err)
+	default:
+		return exchange.Classify(exchange.ErrInvalidOrder, err)
+	}
 }
 
 func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse, error) {
-	if c.exchange == nil {
+	exc, meta := c.trading()
+	if exc == nil {
 		return nil, fmt.Errorf("exchange client not initialized (check private key)")
 	}
 
@@ -136,14 +579,14 @@ func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse
 
 	// Find asset index from meta
 	assetIndex := -1
-	for i, asset := range c.meta.Universe {
+	for i, asset := range meta.Universe {
 		if asset.Name == normalizedSymbol {
 			assetIndex = i
 			break
 		}
 	}
 	if assetIndex == -1 {
-		return nil, fmt.Errorf("symbol %s not found", normalizedSymbol)
+		return nil, fmt.Errorf("%w: symbol %s not in universe", exchange.ErrNotFound, normalizedSymbol)
 	}
 
 	isBuy := req.Side == "buy"
@@ -163,33 +606,61 @@ func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse
 	}
 
 	// Pass nil for builder info
-	res, err := c.exchange.Order(context.Background(), orderReq, nil)
-	if err != nil {
+	if err := c.limiter.Wait(exchange.PriorityTrading, weightAction); err != nil {
 		return nil, err
 	}
+	res, err := exc.Order(context.Background(), orderReq, nil)
+	if err != nil {
+		return nil, classifyOrder(err)
+	}
 
 	// Parse response
-	status := "unknown"
-	var orderID string
-
 	if res.Error != nil {
-		return nil, fmt.Errorf("order failed: %s", *res.Error)
+		return nil, classifyOrder(fmt.Errorf("order failed: %s", *res.Error))
 	}
 
-	if res.Resting != nil {
-		status = "open"
-		orderID = strconv.FormatInt(res.Resting.Oid, 10)
-	} else if res.Filled != nil {
-		status = "filled"
-		orderID = strconv.Itoa(res.Filled.Oid)
+	switch {
+	case res.Resting != nil:
+		return &exchange.OrderResponse{Status: "open", OrderID: strconv.FormatInt(res.Resting.Oid, 10)}, nil
+	case res.Filled != nil:
+		orderRes := &exchange.OrderResponse{Status: "filled", OrderID: strconv.Itoa(res.Filled.Oid)}
+		if orderRes.FilledSize, err = strconv.ParseFloat(res.Filled.TotalSz, 64); err != nil {
+			return nil, fmt.Errorf("order %s filled with invalid size: %w", orderRes.OrderID, err)
+		}
+		if orderRes.AvgPrice, err = strconv.ParseFloat(res.Filled.AvgPx, 64); err != nil {
+			return nil, fmt.Errorf("order %s filled with invalid price: %w", orderRes.OrderID, err)
+		}
+		// An order filled on placement crossed the book, so it paid the taker
+		// rate. The fill stream reports the exact fee.
+		if rate, err := c.takerFeeRate(); err != nil {
+			log.Warn("failed to get fee rate, fill booked without fee", logger.KeyOrderID, orderRes.OrderID, logger.Err(err))
+		} else {
+			orderRes.Fee = orderRes.FilledSize * orderRes.AvgPrice * rate
+		}
+		return orderRes, nil
+	default:
+		// Without an order ID the order could be neither cancelled nor matched
+		// to its fills
+		return nil, classifyOrder(fmt.Errorf("order neither resting nor filled: %s", res.String()))
 	}
-
-	return &exchange.OrderResponse{
-		Status:  status,
-		OrderID: orderID,
-	}, nil
 }
 
 func (c *Client) CancelOrder(symbol, orderID string) error {
-	return fmt.Errorf("not implemented")
+	exc, _ := c.trading()
+	if exc == nil {
+		return fmt.Errorf("exchange client not initialized (check private key)")
+	}
+
+	oid, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+
+	if err := c.limiter.Wait(exchange.PriorityTrading, weightAction); err != nil {
+		return err
+	}
+	if _, err := exc.Cancel(context.Background(), strings.TrimSuffix(symbol, "-USD"), oid); err != nil {
+		return classifyOrder(err)
+	}
+	return nil
 }
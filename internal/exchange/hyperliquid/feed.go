@@ -0,0 +1,398 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/events"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
+	"arbitrage-bot/pkg/ws"
+)
+
+// Key of Hyperliquid data in the market data cache, shared by all accounts
+const exchangeName = "hyperliquid"
+
+// Hyperliquid closes connections that sent nothing for a minute
+const pingInterval = 30 * time.Second
+
+// Feed streams Hyperliquid market data (mids, funding, books) into the
+// market data cache, which Client reads before falling back to REST, and
+// publishes funding and book updates and each tracked account's fills and
+// order updates on the event bus.
+type Feed struct {
+	url   string
+	cache *marketdata.Cache
+	bus   *events.Bus
+
+	market *ws.Client
+	users  []*ws.Client // one per account, as orderUpdates don't name the user
+
+	mu    sync.Mutex
+	coins map[string]bool
+}
+
+// Messages pushed on the subscribed channels
+type wsMessage struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type wsAllMids struct {
+	Mids map[string]string `json:"mids"`
+}
+
+type wsAssetCtx struct {
+	Coin string `json:"coin"`
+	Ctx  struct {
+		Funding string `json:"funding"`
+		MarkPx  string `json:"markPx"`
+		MidPx   string `json:"midPx"`
+	} `json:"ctx"`
+}
+
+type wsBook struct {
+	Coin   string `json:"coin"`
+	Time   int64  `json:"time"`
+	Levels [2][]struct {
+		Px string `json:"px"`
+		Sz string `json:"sz"`
+	} `json:"levels"` // bids, asks
+}
+
+type wsUserFills struct {
+	IsSnapshot bool     `json:"isSnapshot"` // fills from before subscribing
+	Fills      []wsFill `json:"fills"`
+}
+
+type wsFill struct {
+	Coin string `json:"coin"`
+	Px   string `json:"px"`
+	Sz   string `json:"sz"`
+	Side string `json:"side"` // "B" or "A"
+	Time int64  `json:"time"`
+	Oid  int64  `json:"oid"`
+	Tid  int64  `json:"tid"`
+	Fee  string `json:"fee"`
+}
+
+type wsOrderUpdate struct {
+	Order struct {
+		Coin    string `json:"coin"`
+		Side    string `json:"side"`
+		LimitPx string `json:"limitPx"`
+		Sz      string `json:"sz"`
+		Oid     int64  `json:"oid"`
+	} `json:"order"`
+	Status          string `json:"status"`
+	StatusTimestamp int64  `json:"statusTimestamp"`
+}
+
+// NewFeed creates a feed for cfg.WSURL; call Track, TrackAccount and Start.
+func NewFeed(cfg config.HyperliquidConfig, cache *marketdata.Cache, bus *events.Bus) *Feed {
+	f := &Feed{
+		url:   cfg.WSURL,
+		cache: cache,
+		bus:   bus,
+		coins: make(map[string]bool),
+	}
+	f.market = f.newConn(exchangeName, func(state ws.State) {
+		if state != ws.StateConnected {
+			f.cache.Clear(exchangeName)
+		}
+	})
+	// Mids of every coin come in a single subscription
+	f.market.Subscribe(subscription(map[string]any{"type": "allMids"}))
+	return f
+}
+
+func (f *Feed) newConn(venue string, onState func(ws.State)) *ws.Client {
+	return ws.NewClient(ws.Config{
+		Name: venue,
+		URL:  f.url,
+		SubscribeMsg: func(channel string) any {
+			return map[string]any{"method": "subscribe", "subscription": json.RawMessage(channel)}
+		},
+		UnsubscribeMsg: func(channel string) any {
+			return map[string]any{"method": "unsubscribe", "subscription": json.RawMessage(channel)}
+		},
+		Ping:          func() any { return map[string]string{"method": "ping"} },
+		PingInterval:  pingInterval,
+		ReadTimeout:   2 * pingInterval,
+		OnMessage:     func(data []byte) { f.handle(venue, data) },
+		OnStateChange: onState,
+	})
+}
+
+// subscription encodes a subscription object; its JSON is the channel key.
+func subscription(sub map[string]any) string {
+	b, _ := json.Marshal(sub)
+	return string(b)
+}
+
+// Track adds pairs to stream funding rates and books for.
+func (f *Feed) Track(symbols []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, symbol := range symbols {
+		coin := strings.TrimSuffix(symbol, "-USD")
+		if f.coins[coin] {
+			continue
+		}
+		f.coins[coin] = true
+		for _, typ := range []string{"activeAssetCtx", "l2Book"} {
+			if err := f.market.Subscribe(subscription(map[string]any{"type": typ, "coin": coin})); err != nil {
+				log.Warn("failed to subscribe", logger.KeySymbol, symbol, "channel", typ, logger.Err(err))
+			}
+		}
+	}
+}
+
+// TrackAccount streams the fills and order updates of an account, reported
+// under venue. Call it before Start.
+func (f *Feed) TrackAccount(venue string, cfg config.HyperliquidConfig) {
+	user := accountUser(cfg)
+	if user == "" {
+		log.Debug("no address, account events not streamed", logger.KeyVenue, venue)
+		return
+	}
+
+	conn := f.newConn(venue, nil)
+	for _, typ := range []string{"userFills", "orderUpdates"} {
+		conn.Subscribe(subscription(map[string]any{"type": typ, "user": user}))
+	}
+	f.users = append(f.users, conn)
+}
+
+// accountUser returns the address whose orders the account trades, as
+// Client does: the vault if set, else the wallet.
+func accountUser(cfg config.HyperliquidConfig) string {
+	if cfg.VaultAddress != "" {
+		return cfg.VaultAddress
+	}
+	if cfg.WalletAddress != "" {
+		return cfg.WalletAddress
+	}
+	if pk, err := crypto.HexToECDSA(strings.TrimPrefix(string(cfg.PrivateKey), "0x")); err == nil {
+		return crypto.PubkeyToAddress(pk.PublicKey).Hex()
+	}
+	return ""
+}
+
+// Start streams until ctx is cancelled.
+func (f *Feed) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, conn := range append([]*ws.Client{f.market}, f.users...) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn.Run(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (f *Feed) handle(venue string, data []byte) {
+	var msg wsMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Warn("invalid websocket message", logger.KeyVenue, venue, logger.Err(err))
+		return
+	}
+
+	var err error
+	switch msg.Channel {
+	case "allMids":
+		err = f.onMids(msg.Data)
+	case "activeAssetCtx":
+		err = f.onAssetCtx(msg.Data)
+	case "l2Book":
+		err = f.onBook(msg.Data)
+	case "userFills":
+		err = f.onFills(venue, msg.Data)
+	case "orderUpdates":
+		err = f.onOrderUpdates(venue, msg.Data)
+	case "error":
+		log.Error("websocket error message", logger.KeyVenue, venue, "content", string(msg.Data))
+	}
+	if err != nil {
+		log.Warn("invalid websocket message", logger.KeyVenue, venue, "channel", msg.Channel, logger.Err(err))
+	}
+}
+
+func (f *Feed) tracked(coin string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.coins[coin]
+}
+
+// onMids caches the mid prices of the tracked coins, as GetPrice returns
+// over REST.
+func (f *Feed) onMids(data json.RawMessage) error {
+	var mids wsAllMids
+	if err := json.Unmarshal(data, &mids); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for coin, mid := range mids.Mids {
+		if !f.tracked(coin) {
+			continue
+		}
+		price, err := strconv.ParseFloat(mid, 64)
+		if err != nil {
+			return fmt.Errorf("mid of %s: %w", coin, err)
+		}
+		f.cache.SetPrice(exchangeName, coin+"-USD", price, now)
+	}
+	return nil
+}
+
+func (f *Feed) onAssetCtx(data json.RawMessage) error {
+	var ctx wsAssetCtx
+	if err := json.Unmarshal(data, &ctx); err != nil {
+		return err
+	}
+
+	rate, err := strconv.ParseFloat(ctx.Ctx.Funding, 64)
+	if err != nil {
+		return fmt.Errorf("funding of %s: %w", ctx.Coin, err)
+	}
+	now := time.Now()
+	f.cache.SetFundingRate(exchangeName, ctx.Coin+"-USD", rate, now)
+	f.bus.Publish(events.FundingUpdate{Exchange: exchangeName, Symbol: ctx.Coin + "-USD", Rate: rate, Time: now})
+	return nil
+}
+
+func (f *Feed) onBook(data json.RawMessage) error {
+	var book wsBook
+	if err := json.Unmarshal(data, &book); err != nil {
+		return err
+	}
+	out, err := parseBook(book)
+	if err != nil {
+		return err
+	}
+	f.cache.SetBook(exchangeName, book.Coin+"-USD", *out)
+	f.bus.Publish(events.BookUpdate{Exchange: exchangeName, Symbol: book.Coin + "-USD", Book: *out})
+	return nil
+}
+
+// parseBook converts an l2Book, which is always a full snapshot with the
+// best levels first, as pushed and as returned by the info endpoint.
+func parseBook(book wsBook) (*marketdata.Book, error) {
+	out := &marketdata.Book{At: time.Now()}
+	for i, side := range []*[]marketdata.Level{&out.Bids, &out.Asks} {
+		for _, l := range book.Levels[i] {
+			price, err := strconv.ParseFloat(l.Px, 64)
+			if err != nil {
+				return nil, fmt.Errorf("book of %s: %w", book.Coin, err)
+			}
+			size, err := strconv.ParseFloat(l.Sz, 64)
+			if err != nil {
+				return nil, fmt.Errorf("book of %s: %w", book.Coin, err)
+			}
+			*side = append(*side, marketdata.Level{Price: price, Size: size})
+		}
+	}
+	return out, nil
+}
+
+func (f *Feed) onFills(venue string, data json.RawMessage) error {
+	var fills wsUserFills
+	if err := json.Unmarshal(data, &fills); err != nil {
+		return err
+	}
+	// Sent on every (re)subscription; these were known before
+	if fills.IsSnapshot {
+		return nil
+	}
+
+	// A malformed fill is skipped, not published as zero
+	var errs []error
+	for _, fl := range fills.Fills {
+		fill, err := parseFill(venue, fl)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		f.bus.Publish(fill)
+	}
+	return errors.Join(errs...)
+}
+
+func (f *Feed) onOrderUpdates(venue string, data json.RawMessage) error {
+	var updates []wsOrderUpdate
+	if err := json.Unmarshal(data, &updates); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, u := range updates {
+		update, err := parseOrderUpdate(venue, u)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		f.bus.Publish(update)
+	}
+	return errors.Join(errs...)
+}
+
+func parseFill(venue string, fl wsFill) (events.Fill, error) {
+	fill := events.Fill{
+		Venue:   venue,
+		OrderID: strconv.FormatInt(fl.Oid, 10),
+		TradeID: strconv.FormatInt(fl.Tid, 10),
+		Symbol:  fl.Coin + "-USD",
+		Side:    side(fl.Side),
+		Time:    time.UnixMilli(fl.Time),
+	}
+	var err error
+	if fill.Size, err = strconv.ParseFloat(fl.Sz, 64); err != nil {
+		return fill, fmt.Errorf("size of fill %d: %w", fl.Tid, err)
+	}
+	if fill.Price, err = strconv.ParseFloat(fl.Px, 64); err != nil {
+		return fill, fmt.Errorf("price of fill %d: %w", fl.Tid, err)
+	}
+	if fill.Fee, err = strconv.ParseFloat(fl.Fee, 64); err != nil {
+		return fill, fmt.Errorf("fee of fill %d: %w", fl.Tid, err)
+	}
+	return fill, nil
+}
+
+func parseOrderUpdate(venue string, u wsOrderUpdate) (events.OrderUpdate, error) {
+	update := events.OrderUpdate{
+		Venue:   venue,
+		OrderID: strconv.FormatInt(u.Order.Oid, 10),
+		Symbol:  u.Order.Coin + "-USD",
+		Side:    side(u.Order.Side),
+		Status:  u.Status,
+		Time:    time.UnixMilli(u.StatusTimestamp),
+	}
+	var err error
+	if update.Size, err = strconv.ParseFloat(u.Order.Sz, 64); err != nil {
+		return update, fmt.Errorf("size of order %d: %w", u.Order.Oid, err)
+	}
+	if update.Price, err = strconv.ParseFloat(u.Order.LimitPx, 64); err != nil {
+		return update, fmt.Errorf("price of order %d: %w", u.Order.Oid, err)
+	}
+	return update, nil
+}
+
+// side maps Hyperliquid's "B" (bid) and "A" (ask).
+func side(s string) string {
+	if s == "A" {
+		return "sell"
+	}
+	return "buy"
+}
@@ -1,20 +1,36 @@
 package exchange
 
+import (
+	"time"
+
+	"arbitrage-bot/internal/marketdata"
+)
+
 // Exchange defines the common interface for all exchanges
 type Exchange interface {
 	// Market Data
 	GetFundingRate(symbol string) (float64, error)
 	GetPrice(symbol string) (float64, error)
+	// Batch versions, one request per venue where the API allows it.
+	// Symbols the venue doesn't list are left out of the result.
+	GetFundingRates(symbols []string) (map[string]float64, error)
+	GetPrices(symbols []string) (map[string]float64, error)
+	GetOrderBook(symbol string) (*marketdata.Book, error)
 
 	// Account
 	GetBalance(asset string) (float64, error)
 	GetPosition(symbol string) (*Position, error)
+	GetPositions() ([]*Position, error)
+	GetOpenOrders() ([]*Order, error)
+	GetFundingPayments(since time.Time) ([]*FundingPayment, error)
 
 	// Trading
 	PlaceOrder(req *OrderRequest) (*OrderResponse, error)
 	CancelOrder(symbol, orderID string) error
 }
 
+// Position is an open perp position. Size is signed: positive for long,
+// negative for short.
 type Position struct {
 	Symbol        string
 	Size          float64
@@ -22,6 +38,26 @@ type Position struct {
 	UnrealizedPnL float64
 }
 
+// Order is a resting order as reported by the exchange.
+type Order struct {
+	OrderID string
+	Symbol  string
+	Side    string // "buy" or "sell"
+	Size    float64
+	Price   float64
+}
+
+// FundingPayment is a funding settlement on the account. Amount is in USD,
+// positive when funding was received and negative when it was paid.
+type FundingPayment struct {
+	ID     string // unique per exchange
+	Symbol string
+	Amount float64
+	Rate   float64
+	Size   float64 // signed position size at settlement
+	Time   time.Time
+}
+
 type OrderRequest struct {
 	Symbol     string
 	Side       string // "buy" or "sell"
@@ -34,4 +70,9 @@ type OrderRequest struct {
 type OrderResponse struct {
 	OrderID string
 	Status  string
+
+	// Set when the order filled (fully or partly) on placement
+	FilledSize float64
+	AvgPrice   float64
+	Fee        float64
 }
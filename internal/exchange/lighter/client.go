@@ -3,10 +3,15 @@ package lighter
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/elliottech/lighter-go/client"
@@ -16,12 +21,27 @@ import (
 
 	"arbitrage-bot/internal/config"
 	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
 )
 
+var log = logger.For("lighter")
+
 type Client struct {
 	cfg        config.LighterConfig
 	httpClient *http.Client
-	txClient   *client.TxClient
+	limiter    *exchange.Limiter
+	market     *marketdata.Cache // fed by Feed, nil without one
+
+	mu       sync.RWMutex
+	txClient *client.TxClient // nil without credentials or until created
+
+	lastClientIndex atomic.Int64 // see nextClientOrderIndex
+
+	// Last /funding-rates response; see fundingRates
+	snapMu     sync.Mutex
+	snapshot   map[string]float64
+	snapshotAt time.Time
 }
 
 // Lighter API Response structures
@@ -37,117 +57,518 @@ type FundingRate struct {
 	Rate     float64 `json:"rate"`
 }
 
+type AccountResponse struct {
+	Code     int       `json:"code"`
+	Accounts []Account `json:"accounts"`
+}
+
+type Account struct {
+	Index      int64             `json:"index"`
+	Collateral string            `json:"collateral"`
+	Positions  []AccountPosition `json:"positions"`
+}
+
+type AccountPosition struct {
+	MarketId      int    `json:"market_id"`
+	Symbol        string `json:"symbol"`
+	Sign          int    `json:"sign"` // 1 for long, -1 for short
+	Position      string `json:"position"`
+	AvgEntryPrice string `json:"avg_entry_price"`
+	UnrealizedPnl string `json:"unrealized_pnl"`
+}
+
+type ActiveOrdersResponse struct {
+	Code   int           `json:"code"`
+	Orders []ActiveOrder `json:"orders"`
+}
+
+type ActiveOrder struct {
+	OrderIndex          int64  `json:"order_index"`
+	ClientOrderIndex    int64  `json:"client_order_index"`
+	MarketIndex         int    `json:"market_index"`
+	IsAsk               bool   `json:"is_ask"`
+	Price               string `json:"price"`
+	RemainingBaseAmount string `json:"remaining_base_amount"`
+}
+
+type SendTxResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	TxHash  string `json:"tx_hash"`
+}
+
+type PositionFundingResponse struct {
+	Code             int               `json:"code"`
+	PositionFundings []PositionFunding `json:"position_fundings"`
+	NextCursor       string            `json:"next_cursor"`
+}
+
+type PositionFunding struct {
+	Timestamp    int64  `json:"timestamp"`
+	MarketId     int    `json:"market_id"`
+	FundingId    int64  `json:"funding_id"`
+	Change       string `json:"change"`
+	Rate         string `json:"rate"`
+	PositionSize string `json:"position_size"`
+	PositionSide string `json:"position_side"` // "long" or "short"
+}
+
 const (
 	LighterChainId = 1 // Mainnet chain ID, adjust if needed
 )
 
-func NewClient(cfg config.LighterConfig) *Client {
+// marketIndexes maps base symbols to Lighter market indexes.
+// In production, fetch this from /api/v1/markets endpoint
+var marketIndexes = map[string]uint16{
+	"ETH":  1,
+	"BTC":  2,
+	"SOL":  3,
+	"AVAX": 4,
+	// Add more as needed (and to config.knownPairs)
+}
+
+// Standard Lighter accounts get 60 requests per minute regardless of the
+// endpoint. Placing or cancelling an order is two requests (nonce, sendTx).
+const (
+	defaultWeightPerMinute = 60
+	weightRequest          = 1
+	weightTx               = 2
+)
+
+// Market data is reused for this long, so the lookups of one strategy tick
+// share a single request
+const snapshotTTL = 500 * time.Millisecond
+
+// NewLimiter creates the limiter shared by all Lighter accounts.
+func NewLimiter(cfg config.LighterConfig) *exchange.Limiter {
+	weight := cfg.RateLimitPerMinute
+	if weight == 0 {
+		weight = defaultWeightPerMinute
+	}
+	return exchange.NewLimiter("lighter", weight)
+}
+
+// NewClient creates a client. limiter and market may be nil.
+func NewClient(cfg config.LighterConfig, limiter *exchange.Limiter, market *marketdata.Cache) *Client {
 	c := &Client{
-		cfg: cfg,
+		cfg:     cfg,
+		limiter: limiter,
+		market:  market,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 	}
 
 	// Initialize TxClient if private key is configured
-	if cfg.PrivateKey != "" && cfg.APIKey != "" {
-		httpCli := lighterhttp.NewClient(cfg.BaseURL)
-
-		// CreateClient(httpClient, privateKey, chainId, apiKeyIndex, accountIndex)
-		// Using default values: apiKeyIndex=0, accountIndex=1
-		txClient, err := client.CreateClient(httpCli, cfg.PrivateKey, LighterChainId, 0, 1)
-		if err != nil {
-			fmt.Printf("Warning: Failed to create Lighter TxClient: %v\n", err)
-		} else {
-			c.txClient = txClient
+	if c.hasCredentials() {
+		if err := c.initTxClient(); err != nil {
+			log.Warn("failed to create TxClient", logger.Err(err))
+		} else if err := c.tx().Check(); err != nil {
 			// Verify the client
-			if err := txClient.Check(); err != nil {
-				fmt.Printf("Warning: Lighter TxClient check failed: %v\n", err)
-			} else {
-				fmt.Println("Lighter TxClient initialized successfully")
-			}
+			log.Warn("TxClient check failed", logger.Err(err))
+		} else {
+			log.Info("TxClient initialized")
 		}
 	}
 
 	return c
 }
 
-var _ exchange.Exchange = (*Client)(nil)
+func (c *Client) hasCredentials() bool {
+	return c.cfg.PrivateKey != "" && c.cfg.APIKey != ""
+}
 
-func (c *Client) GetFundingRate(symbol string) (float64, error) {
-	// Normalize: ETH-USD -> ETH
-	normalizedSymbol := strings.TrimSuffix(symbol, "-USD")
-	normalizedSymbol = strings.TrimSuffix(normalizedSymbol, "USDT")
+func (c *Client) initTxClient() error {
+	httpCli := lighterhttp.NewClient(c.cfg.BaseURL)
 
-	url := c.cfg.BaseURL + "/api/v1/funding-rates"
+	// CreateClient(httpClient, privateKey, chainId, apiKeyIndex, accountIndex)
+	txClient, err := client.CreateClient(httpCli, string(c.cfg.PrivateKey), LighterChainId, c.cfg.APIKeyIndex, c.cfg.AccountIndex)
+	if err != nil {
+		return err
+	}
 
-	resp, err := c.httpClient.Get(url)
+	c.mu.Lock()
+	c.txClient = txClient
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) tx() *client.TxClient {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.txClient
+}
+
+// CheckReady verifies the API key with Lighter, creating the TxClient first
+// if that failed at startup. Without credentials only public data is used
+// and the client is always ready.
+func (c *Client) CheckReady() error {
+	if !c.hasCredentials() {
+		return nil
+	}
+	if c.tx() == nil {
+		if err := c.initTxClient(); err != nil {
+			return fmt.Errorf("failed to create TxClient: %w", err)
+		}
+	}
+	if err := c.limiter.Wait(exchange.PriorityData, weightRequest); err != nil {
+		return err
+	}
+	if err := c.tx().Check(); err != nil {
+		return fmt.Errorf("TxClient check failed: %w", err)
+	}
+	return nil
+}
+
+var _ exchange.Exchange = (*Client)(nil)
+
+func (c *Client) GetFundingRate(symbol string) (float64, error) {
+	rates, err := c.GetFundingRates([]string{symbol})
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
+	rate, ok := rates[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: funding rate for symbol %s", exchange.ErrNotFound, normalizeSymbol(symbol))
+	}
+	return rate, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// GetFundingRates reads every symbol from a single /funding-rates request.
+func (c *Client) GetFundingRates(symbols []string) (map[string]float64, error) {
+	all, err := c.fundingRates()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	rates := make(map[string]float64, len(symbols))
+	for _, symbol := range symbols {
+		if rate, ok := all[normalizeSymbol(symbol)]; ok {
+			rates[symbol] = rate
+		}
 	}
+	return rates, nil
+}
+
+// normalizeSymbol converts ETH-USD (or ETHUSDT) to ETH
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSuffix(strings.TrimSuffix(symbol, "-USD"), "USDT"))
+}
+
+// fundingRates returns the rates by upper-case symbol, reusing the last
+// response if it is younger than snapshotTTL.
+func (c *Client) fundingRates() (map[string]float64, error) {
+	c.snapMu.Lock()
+	defer c.snapMu.Unlock()
+
+	if c.snapshot != nil && time.Since(c.snapshotAt) < snapshotTTL {
+		return c.snapshot, nil
+	}
+
+	url := c.cfg.BaseURL + "/api/v1/funding-rates"
 
 	var fundingResp FundingRatesResponse
-	if err := json.Unmarshal(body, &fundingResp); err != nil {
-		return 0, err
+	if err := c.getJSON("fundingRates", url, &fundingResp); err != nil {
+		return nil, err
 	}
 
 	if fundingResp.Code != 200 {
-		return 0, fmt.Errorf("API error code: %d", fundingResp.Code)
+		return nil, fmt.Errorf("API error code: %d", fundingResp.Code)
 	}
 
-	// Find the funding rate for the symbol
+	// The list also carries other exchanges' rates; Lighter's own win
+	rates := make(map[string]float64, len(fundingResp.FundingRates))
 	for _, fr := range fundingResp.FundingRates {
-		if strings.EqualFold(fr.Symbol, normalizedSymbol) {
-			return fr.Rate, nil
+		symbol := strings.ToUpper(fr.Symbol)
+		if _, seen := rates[symbol]; !seen || fr.Exchange == "lighter" {
+			rates[symbol] = fr.Rate
 		}
 	}
-
-	return 0, fmt.Errorf("funding rate not found for symbol: %s", normalizedSymbol)
+	c.snapshot = rates
+	c.snapshotAt = time.Now()
+	return rates, nil
 }
 
 // addAuthHeaders adds authentication headers to the request if API key is configured
 func (c *Client) addAuthHeaders(req *http.Request) {
 	if c.cfg.APIKey != "" {
-		req.Header.Set("X-API-KEY", c.cfg.APIKey)
+		req.Header.Set("X-API-KEY", string(c.cfg.APIKey))
 		// Lighter may use different header names, adjust as needed
 		// Common alternatives: "Authorization", "api-key", etc.
 	}
 }
 
 // makeAuthenticatedRequest creates and executes an authenticated HTTP request
-func (c *Client) makeAuthenticatedRequest(method, url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, url, body)
+func (c *Client) makeAuthenticatedRequest(method, target string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, body)
 	if err != nil {
-		return nil, err
+		return nil, withoutQuery(err)
 	}
 
 	c.addAuthHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
 
-	return c.httpClient.Do(req)
+	resp, err := c.httpClient.Do(req)
+	return resp, withoutQuery(err)
 }
 
+// withoutQuery strips the query string, which may carry the auth token,
+// from the URL of a request error.
+func withoutQuery(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL, _, _ = strings.Cut(urlErr.URL, "?")
+	}
+	return err
+}
+
+// GetPrice returns the mid price of the order book kept by the WebSocket
+// feed; Lighter has no REST price endpoint.
 func (c *Client) GetPrice(symbol string) (float64, error) {
-	// TODO: Implement using orderbook or ticker endpoint
-	return 0, fmt.Errorf("not implemented - use orderbook endpoint")
+	prices, err := c.GetPrices([]string{symbol})
+	if err != nil {
+		return 0, err
+	}
+	price, ok := prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%w: no fresh order book for %s", exchange.ErrNotFound, symbol)
+	}
+	return price, nil
+}
+
+func (c *Client) GetPrices(symbols []string) (map[string]float64, error) {
+	if c.market == nil || c.cfg.WSURL == "" {
+		return nil, fmt.Errorf("%w - requires the WebSocket feed (ws_url)", exchange.ErrNotImplemented)
+	}
+	prices, _ := c.market.Prices(exchangeName, symbols)
+	return prices, nil
+}
+
+// GetOrderBook returns the book kept by the WebSocket feed.
+func (c *Client) GetOrderBook(symbol string) (*marketdata.Book, error) {
+	if c.market == nil || c.cfg.WSURL == "" {
+		return nil, fmt.Errorf("%w - requires the WebSocket feed (ws_url)", exchange.ErrNotImplemented)
+	}
+	book, ok := c.market.Book(exchangeName, symbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: no fresh order book for %s", exchange.ErrNotFound, symbol)
+	}
+	return &book, nil
 }
 
 func (c *Client) GetBalance(asset string) (float64, error) {
-	return 0, fmt.Errorf("not implemented - requires authentication")
+	account, err := c.getAccount()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(account.Collateral, 64)
 }
 
 func (c *Client) GetPosition(symbol string) (*exchange.Position, error) {
-	return nil, fmt.Errorf("not implemented - requires authentication")
+	positions, err := c.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pos := range positions {
+		if pos.Symbol == symbol {
+			return pos, nil
+		}
+	}
+	return &exchange.Position{Symbol: symbol}, nil
+}
+
+func (c *Client) GetPositions() ([]*exchange.Position, error) {
+	account, err := c.getAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]*exchange.Position, 0, len(account.Positions))
+	for _, p := range account.Positions {
+		size, err := strconv.ParseFloat(p.Position, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse position for %s: %w", p.Symbol, err)
+		}
+		if size == 0 {
+			continue
+		}
+		if p.Sign < 0 {
+			size = -size
+		}
+
+		pos := &exchange.Position{
+			Symbol: p.Symbol + "-USD",
+			Size:   size,
+		}
+		pos.EntryPrice, _ = strconv.ParseFloat(p.AvgEntryPrice, 64)
+		pos.UnrealizedPnL, _ = strconv.ParseFloat(p.UnrealizedPnl, 64)
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
+
+func (c *Client) GetOpenOrders() ([]*exchange.Order, error) {
+	txClient := c.tx()
+	if txClient == nil {
+		return nil, fmt.Errorf("txClient not initialized - requires authentication")
+	}
+
+	auth, err := txClient.GetAuthToken(time.Now().Add(time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth token: %w", err)
+	}
+
+	// Active orders are only queryable per market
+	var orders []*exchange.Order
+	for symbol, marketIndex := range marketIndexes {
+		url := fmt.Sprintf("%s/api/v1/accountActiveOrders?account_index=%d&market_id=%d&auth=%s",
+			c.cfg.BaseURL, c.cfg.AccountIndex, marketIndex, auth)
+
+		var ordersResp ActiveOrdersResponse
+		if err := c.getJSON("accountActiveOrders", url, &ordersResp); err != nil {
+			return nil, err
+		}
+		if ordersResp.Code != 200 {
+			return nil, fmt.Errorf("API error code: %d", ordersResp.Code)
+		}
+
+		for _, o := range ordersResp.Orders {
+			side := "buy"
+			if o.IsAsk {
+				side = "sell"
+			}
+			order := &exchange.Order{
+				OrderID: orderID(o.OrderIndex, o.ClientOrderIndex),
+				Symbol:  symbol + "-USD",
+				Side:    side,
+			}
+			order.Size, _ = strconv.ParseFloat(o.RemainingBaseAmount, 64)
+			order.Price, _ = strconv.ParseFloat(o.Price, 64)
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (c *Client) GetFundingPayments(since time.Time) ([]*exchange.FundingPayment, error) {
+	txClient := c.tx()
+	if txClient == nil {
+		return nil, fmt.Errorf("txClient not initialized - requires authentication")
+	}
+
+	auth, err := txClient.GetAuthToken(time.Now().Add(time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth token: %w", err)
+	}
+
+	// Pages come newest first; keep going until one reaches since
+	var fundings []PositionFunding
+	cursor := ""
+	for {
+		page := ""
+		if cursor != "" {
+			page = "&cursor=" + url.QueryEscape(cursor)
+		}
+		url := fmt.Sprintf("%s/api/v1/positionFunding?account_index=%d&limit=100&auth=%s%s",
+			c.cfg.BaseURL, c.cfg.AccountIndex, auth, page)
+
+		var fundingResp PositionFundingResponse
+		if err := c.getJSON("positionFunding", url, &fundingResp); err != nil {
+			return nil, err
+		}
+		if fundingResp.Code != 200 {
+			return nil, fmt.Errorf("API error code: %d", fundingResp.Code)
+		}
+		fundings = append(fundings, fundingResp.PositionFundings...)
+
+		n := len(fundingResp.PositionFundings)
+		if fundingResp.NextCursor == "" || n == 0 ||
+			time.Unix(fundingResp.PositionFundings[n-1].Timestamp, 0).Before(since) {
+			break
+		}
+		cursor = fundingResp.NextCursor
+	}
+
+	var payments []*exchange.FundingPayment
+	for _, f := range fundings {
+		paidAt := time.Unix(f.Timestamp, 0)
+		if paidAt.Before(since) {
+			continue
+		}
+
+		symbol, ok := marketSymbol(f.MarketId)
+		if !ok {
+			symbol = fmt.Sprintf("MARKET%d", f.MarketId)
+		}
+
+		payment := &exchange.FundingPayment{
+			ID:     fmt.Sprintf("%d/%d", f.FundingId, f.MarketId),
+			Symbol: symbol + "-USD",
+			Time:   paidAt,
+		}
+		payment.Amount, _ = strconv.ParseFloat(f.Change, 64)
+		payment.Rate, _ = strconv.ParseFloat(f.Rate, 64)
+		payment.Size, _ = strconv.ParseFloat(f.PositionSize, 64)
+		if f.PositionSide == "short" {
+			payment.Size = -payment.Size
+		}
+		payments = append(payments, payment)
+	}
+	return payments, nil
+}
+
+// getAccount fetches the configured account, including its positions
+func (c *Client) getAccount() (*Account, error) {
+	url := fmt.Sprintf("%s/api/v1/account?by=index&value=%d", c.cfg.BaseURL, c.cfg.AccountIndex)
+
+	var accountResp AccountResponse
+	if err := c.getJSON("account", url, &accountResp); err != nil {
+		return nil, err
+	}
+	if accountResp.Code != 200 {
+		return nil, fmt.Errorf("API error code: %d", accountResp.Code)
+	}
+	if len(accountResp.Accounts) == 0 {
+		return nil, fmt.Errorf("%w: account %d", exchange.ErrNotFound, c.cfg.AccountIndex)
+	}
+	return &accountResp.Accounts[0], nil
+}
+
+// getJSON performs an authenticated GET and decodes the JSON body into out,
+// retrying failures that may go away. op names the request in logs.
+func (c *Client) getJSON(op, target string, out interface{}) error {
+	body, err := exchange.Retry(op, func() ([]byte, error) {
+		if err := c.limiter.Wait(exchange.PriorityData, weightRequest); err != nil {
+			return nil, err
+		}
+		resp, err := c.makeAuthenticatedRequest("GET", target, nil)
+		if err != nil {
+			return nil, exchange.ClassifyNetwork(err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, exchange.ClassifyNetwork(err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, exchange.ClassifyStatus(resp.StatusCode,
+				fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body)))
+		}
+		return body, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, out)
 }
 
 func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse, error) {
-	if c.txClient == nil {
+	txClient := c.tx()
+	if txClient == nil {
 		return nil, fmt.Errorf("txClient not initialized - check private_key and api_key configuration")
 	}
 
@@ -186,10 +607,14 @@ func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse
 		reduceOnly = 1
 	}
 
+	// The order index is assigned on execution and not returned, so the
+	// order is known by the client order index set here
+	clientIndex := c.nextClientOrderIndex()
+
 	// Create order request
 	orderReq := &types.CreateOrderTxReq{
 		MarketIndex:      uint8(marketIndex),
-		ClientOrderIndex: time.Now().UnixNano(),
+		ClientOrderIndex: clientIndex,
 		BaseAmount:       sizeInt,
 		Price:            priceInt,
 		IsAsk:            isAsk,
@@ -200,8 +625,12 @@ func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse
 		OrderExpiry:      time.Now().Add(24 * time.Hour).Unix(),
 	}
 
+	if err := c.limiter.Wait(exchange.PriorityTrading, weightTx); err != nil {
+		return nil, err
+	}
+
 	// Get signed transaction
-	txInfo, err := c.txClient.GetCreateOrderTransaction(orderReq, nil)
+	txInfo, err := txClient.GetCreateOrderTransaction(orderReq, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create order transaction: %w", err)
 	}
@@ -212,43 +641,122 @@ func (c *Client) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse
 		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
 	}
 
-	// Send the signed order to the exchange
-	orderURL := c.cfg.BaseURL + "/api/v1/orders"
-	resp, err := c.makeAuthenticatedRequest("POST", orderURL, bytes.NewBufferString(txJSON))
-	if err != nil {
-		return nil, fmt.Errorf("failed to send order: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Parse response
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := c.submitTx(txJSON)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("order failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, err
 	}
 
-	// Parse order response
-	var orderResp map[string]interface{}
-	if err := json.Unmarshal(respBody, &orderResp); err != nil {
+	var txResp SendTxResponse
+	if err := json.Unmarshal(respBody, &txResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	if txResp.Code != 200 {
+		return nil, classifyTx(http.StatusOK, fmt.Errorf("transaction rejected with code %d: %s", txResp.Code, txResp.Message))
+	}
 
 	return &exchange.OrderResponse{
 		Status:  "submitted",
-		OrderID: fmt.Sprintf("%v", orderResp["order_id"]),
+		OrderID: strconv.FormatInt(clientIndex, 10),
 	}, nil
 }
 
+// nextClientOrderIndex returns a client order index unique to this account:
+// the current time in milliseconds, or one past the last index if orders
+// come faster than that. Lighter caps client indexes at 2^48-1, far beyond
+// any millisecond timestamp.
+func (c *Client) nextClientOrderIndex() int64 {
+	for {
+		last := c.lastClientIndex.Load()
+		next := max(last+1, time.Now().UnixMilli(), txtypes.MinClientOrderIndex)
+		if c.lastClientIndex.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// orderID is how an order is known to callers: by the client order index
+// PlaceOrder set, or by the order index for orders placed elsewhere.
+func orderID(orderIndex, clientIndex int64) string {
+	if clientIndex != txtypes.NilClientOrderIndex {
+		return strconv.FormatInt(clientIndex, 10)
+	}
+	return strconv.FormatInt(orderIndex, 10)
+}
+
 func (c *Client) CancelOrder(symbol, orderID string) error {
-	if c.txClient == nil {
+	txClient := c.tx()
+	if txClient == nil {
 		return fmt.Errorf("txClient not initialized")
 	}
 
-	// TODO: Implement cancel order using SDK
-	return fmt.Errorf("not implemented")
+	marketIndex, err := c.getMarketIndex(symbol)
+	if err != nil {
+		return err
+	}
+
+	// Either a client order index or an order index; Lighter tells them
+	// apart by range
+	orderIndex, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+
+	if err := c.limiter.Wait(exchange.PriorityTrading, weightTx); err != nil {
+		return err
+	}
+	txInfo, err := txClient.GetCancelOrderTransaction(&types.CancelOrderTxReq{
+		MarketIndex: uint8(marketIndex),
+		Index:       orderIndex,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create cancel transaction: %w", err)
+	}
+
+	txJSON, err := txInfo.GetTxInfo()
+	if err != nil {
+		return fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	_, err = c.submitTx(txJSON)
+	return err
+}
+
+// submitTx sends a signed transaction to the exchange and returns the raw response body
+func (c *Client) submitTx(txJSON string) ([]byte, error) {
+	orderURL := c.cfg.BaseURL + "/api/v1/orders"
+	resp, err := c.makeAuthenticatedRequest("POST", orderURL, bytes.NewBufferString(txJSON))
+	if err != nil {
+		return nil, exchange.ClassifyNetwork(fmt.Errorf("failed to send transaction: %w", err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, exchange.ClassifyNetwork(fmt.Errorf("failed to read response: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, classifyTx(resp.StatusCode, fmt.Errorf("transaction failed with status %d: %s", resp.StatusCode, string(respBody)))
+	}
+	return respBody, nil
+}
+
+// classifyTx maps a rejected transaction to an exchange error class. Lighter
+// only explains rejections in the message.
+func classifyTx(status int, err error) error {
+	err = exchange.ClassifyStatus(status, err)
+	if exchange.Class(err) != "unknown" {
+		return err
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "margin"):
+		return exchange.Classify(exchange.ErrInsufficientMargin, err)
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "does not exist"):
+		return exchange.Classify(exchange.ErrNotFound, err)
+	default:
+		return exchange.Classify(exchange.ErrInvalidOrder, err)
+	}
 }
 
 // getMarketIndex converts symbol to Lighter market index
@@ -257,19 +765,19 @@ func (c *Client) getMarketIndex(symbol string) (uint16, error) {
 	// Normalize symbol
 	normalizedSymbol := strings.TrimSuffix(symbol, "-USD")
 
-	// Hardcoded mapping for common pairs
-	// In production, fetch this from /api/v1/markets endpoint
-	marketMap := map[string]uint16{
-		"ETH":  1,
-		"BTC":  2,
-		"SOL":  3,
-		"AVAX": 4,
-		// Add more as needed
-	}
-
-	if marketIndex, ok := marketMap[normalizedSymbol]; ok {
+	if marketIndex, ok := marketIndexes[normalizedSymbol]; ok {
 		return marketIndex, nil
 	}
 
-	return 0, fmt.Errorf("unknown market: %s", symbol)
+	return 0, fmt.Errorf("%w: unknown market %s", exchange.ErrNotFound, symbol)
+}
+
+// marketSymbol is the reverse of getMarketIndex
+func marketSymbol(marketIndex int) (string, bool) {
+	for symbol, idx := range marketIndexes {
+		if int(idx) == marketIndex {
+			return symbol, true
+		}
+	}
+	return "", false
 }
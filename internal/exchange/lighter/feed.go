@@ -0,0 +1,543 @@
+package lighter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elliottech/lighter-go/client"
+	lighterhttp "github.com/elliottech/lighter-go/client/http"
+	"github.com/elliottech/lighter-go/types/txtypes"
+
+	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/events"
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/marketdata"
+	"arbitrage-bot/pkg/ws"
+)
+
+// Key of Lighter data in the market data cache, shared by all accounts
+const exchangeName = "lighter"
+
+// Order book levels kept in the cache per side
+const bookLevels = 50
+
+// Lighter pings every connection, so a quiet spell this long means it's dead
+const readTimeout = 2 * time.Minute
+
+// Lifetime of the auth token sent when subscribing to account orders; the
+// server accepts at most 8 hours
+const authTokenTTL = 7 * time.Hour
+
+// Feed maintains local Lighter order books from the WebSocket order book
+// deltas, publishing them and their mid prices to the market data cache,
+// and publishes the books and each tracked account's trades, orders and
+// positions on the event bus.
+type Feed struct {
+	url   string
+	cache *marketdata.Cache
+	bus   *events.Bus
+
+	market *ws.Client
+	users  []*ws.Client
+
+	mu      sync.Mutex
+	markets map[uint16]bool
+	books   map[uint16]*localBook
+}
+
+// localBook is a market's order book, size by price. Lighter numbers its
+// messages with an offset, and updates carry the nonce range they cover;
+// an update that doesn't continue from the last one means one was missed.
+type localBook struct {
+	bids   map[float64]float64
+	asks   map[float64]float64
+	offset int64
+	nonce  int64
+}
+
+// Messages pushed on the subscribed channels
+type wsMessage struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+}
+
+type wsOrderBookMessage struct {
+	Channel   string `json:"channel"`
+	Offset    int64  `json:"offset"`
+	OrderBook struct {
+		Asks       []wsLevel `json:"asks"`
+		Bids       []wsLevel `json:"bids"`
+		Offset     int64     `json:"offset"`
+		Nonce      int64     `json:"nonce"`
+		BeginNonce int64     `json:"begin_nonce"`
+	} `json:"order_book"`
+}
+
+type wsLevel struct {
+	Price string `json:"price"`
+	Size  string `json:"size"` // 0 removes the level
+}
+
+type wsAccountMessage struct {
+	Positions map[string]AccountPosition `json:"positions"` // by market index
+	Trades    map[string][]wsTrade       `json:"trades"`    // by market index
+}
+
+type wsTrade struct {
+	TradeId      int64  `json:"trade_id"`
+	MarketId     int    `json:"market_id"`
+	Size         string `json:"size"`
+	Price        string `json:"price"`
+	AskId        int64  `json:"ask_id"`
+	BidId        int64  `json:"bid_id"`
+	AskClientId  int64  `json:"ask_client_id"`
+	BidClientId  int64  `json:"bid_client_id"`
+	AskAccountId int64  `json:"ask_account_id"`
+	BidAccountId int64  `json:"bid_account_id"`
+	IsMakerAsk   bool   `json:"is_maker_ask"`
+	TakerFee     int64  `json:"taker_fee"` // in millionths of the notional
+	MakerFee     int64  `json:"maker_fee"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+type wsOrdersMessage struct {
+	Orders map[string][]wsOrder `json:"orders"` // by market index
+}
+
+type wsOrder struct {
+	OrderIndex          int64  `json:"order_index"`
+	ClientOrderIndex    int64  `json:"client_order_index"`
+	MarketIndex         int    `json:"market_index"`
+	IsAsk               bool   `json:"is_ask"`
+	Price               string `json:"price"`
+	RemainingBaseAmount string `json:"remaining_base_amount"`
+	Status              string `json:"status"`
+	Timestamp           int64  `json:"timestamp"`
+}
+
+// NewFeed creates a feed for cfg.WSURL; call Track, TrackAccount and Start.
+func NewFeed(cfg config.LighterConfig, cache *marketdata.Cache, bus *events.Bus) *Feed {
+	f := &Feed{
+		url:     cfg.WSURL,
+		cache:   cache,
+		bus:     bus,
+		markets: make(map[uint16]bool),
+		books:   make(map[uint16]*localBook),
+	}
+	f.market = f.newConn(exchangeName, 0, nil, func(state ws.State) {
+		if state != ws.StateConnected {
+			// Books start over from the snapshots sent on resubscription
+			f.mu.Lock()
+			clear(f.books)
+			f.mu.Unlock()
+			f.cache.Clear(exchangeName)
+		}
+	})
+	return f
+}
+
+// newConn creates a connection whose events are reported under venue, for
+// account (0 for market data). auth, if set, returns the token to subscribe
+// with.
+func (f *Feed) newConn(venue string, account int64, auth func() (string, error), onState func(ws.State)) *ws.Client {
+	var conn *ws.Client
+	conn = ws.NewClient(ws.Config{
+		Name: venue,
+		URL:  f.url,
+		SubscribeMsg: func(channel string) any {
+			msg := map[string]string{"type": "subscribe", "channel": channel}
+			if auth != nil {
+				token, err := auth()
+				if err != nil {
+					log.Warn("failed to create auth token", logger.KeyVenue, venue, logger.Err(err))
+				}
+				msg["auth"] = token
+			}
+			return msg
+		},
+		UnsubscribeMsg: func(channel string) any {
+			return map[string]string{"type": "unsubscribe", "channel": channel}
+		},
+		ReadTimeout:   readTimeout,
+		OnMessage:     func(data []byte) { f.handle(conn, venue, account, data) },
+		OnStateChange: onState,
+	})
+	return conn
+}
+
+// Track adds pairs to keep order books for. Pairs without a known market
+// index are skipped.
+func (f *Feed) Track(symbols []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, symbol := range symbols {
+		marketIndex, ok := marketIndexes[strings.TrimSuffix(symbol, "-USD")]
+		if !ok {
+			log.Warn("pair not streamed, unknown market", logger.KeySymbol, symbol)
+			continue
+		}
+		if f.markets[marketIndex] {
+			continue
+		}
+		f.markets[marketIndex] = true
+		if err := f.market.Subscribe(bookChannel(marketIndex)); err != nil {
+			log.Warn("failed to subscribe", logger.KeySymbol, symbol, "channel", "order_book", logger.Err(err))
+		}
+	}
+}
+
+func bookChannel(marketIndex uint16) string {
+	return fmt.Sprintf("order_book/%d", marketIndex)
+}
+
+// TrackAccount streams the trades and positions of an account, and its
+// orders if it has credentials, reported under venue. Call it before Start.
+func (f *Feed) TrackAccount(venue string, cfg config.LighterConfig) {
+	var auth func() (string, error)
+	if cfg.PrivateKey != "" {
+		txClient, err := client.NewTxClient(lighterhttp.NewClient(cfg.BaseURL), string(cfg.PrivateKey),
+			cfg.AccountIndex, cfg.APIKeyIndex, LighterChainId)
+		if err != nil {
+			log.Warn("orders not streamed, invalid key", logger.KeyVenue, venue, logger.Err(err))
+		} else {
+			auth = func() (string, error) {
+				return txClient.GetAuthToken(time.Now().Add(authTokenTTL))
+			}
+		}
+	}
+
+	conn := f.newConn(venue, cfg.AccountIndex, auth, nil)
+	conn.Subscribe(fmt.Sprintf("account_all/%d", cfg.AccountIndex))
+	if auth != nil {
+		conn.Subscribe(fmt.Sprintf("account_all_orders/%d", cfg.AccountIndex))
+	}
+	f.users = append(f.users, conn)
+}
+
+// Start streams until ctx is cancelled.
+func (f *Feed) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, conn := range append([]*ws.Client{f.market}, f.users...) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn.Run(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (f *Feed) handle(conn *ws.Client, venue string, account int64, data []byte) {
+	var msg wsMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Warn("invalid websocket message", logger.KeyVenue, venue, logger.Err(err))
+		return
+	}
+
+	// "subscribed/<channel>" carries the snapshot, "update/<channel>" changes
+	kind, channel, _ := strings.Cut(msg.Type, "/")
+	snapshot := kind == "subscribed"
+
+	var err error
+	switch channel {
+	case "order_book":
+		err = f.onOrderBook(snapshot, data)
+	case "account_all":
+		err = f.onAccount(venue, account, snapshot, data)
+	case "account_all_orders":
+		err = f.onOrders(venue, data)
+	default:
+		switch msg.Type {
+		case "ping":
+			if err := conn.Send(map[string]string{"type": "pong"}); err != nil {
+				log.Warn("pong failed", logger.KeyVenue, venue, logger.Err(err))
+			}
+		case "error":
+			log.Error("websocket error message", logger.KeyVenue, venue, "content", string(data))
+		}
+	}
+	if err != nil {
+		log.Warn("invalid websocket message", logger.KeyVenue, venue, "channel", msg.Channel, logger.Err(err))
+	}
+}
+
+// onOrderBook applies a snapshot or delta to the local book. If a delta
+// doesn't follow the previous message the book is dropped and the market
+// resubscribed, which sends a new snapshot.
+func (f *Feed) onOrderBook(snapshot bool, data []byte) error {
+	var msg wsOrderBookMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	// Channel is "order_book:<market index>"
+	_, idx, _ := strings.Cut(msg.Channel, ":")
+	n, err := strconv.ParseUint(idx, 10, 16)
+	if err != nil {
+		return fmt.Errorf("channel %q: %w", msg.Channel, err)
+	}
+	marketIndex := uint16(n)
+	symbol, ok := marketSymbol(int(marketIndex))
+	if !ok {
+		return nil
+	}
+	symbol += "-USD"
+
+	ob := msg.OrderBook
+	offset := max(msg.Offset, ob.Offset)
+	bids, err := parseLevels(ob.Bids)
+	if err != nil {
+		return fmt.Errorf("bids of %s: %w", symbol, err)
+	}
+	asks, err := parseLevels(ob.Asks)
+	if err != nil {
+		return fmt.Errorf("asks of %s: %w", symbol, err)
+	}
+
+	f.mu.Lock()
+	book := f.books[marketIndex]
+	switch {
+	case snapshot:
+		book = &localBook{bids: make(map[float64]float64), asks: make(map[float64]float64)}
+		f.books[marketIndex] = book
+	case book == nil:
+		// Waiting for the snapshot after a resync
+		f.mu.Unlock()
+		return nil
+	case !book.follows(offset, ob.BeginNonce):
+		delete(f.books, marketIndex)
+		f.mu.Unlock()
+
+		log.Warn("order book out of sequence, resyncing", logger.KeySymbol, symbol,
+			"offset", offset, "last_offset", book.offset, "begin_nonce", ob.BeginNonce, "last_nonce", book.nonce)
+		f.resync(marketIndex)
+		return nil
+	}
+
+	book.apply(bids, asks)
+	book.offset = offset
+	book.nonce = ob.Nonce
+	out := marketdata.Book{
+		Bids: topLevels(book.bids, true),
+		Asks: topLevels(book.asks, false),
+		At:   time.Now(),
+	}
+	f.mu.Unlock()
+
+	f.cache.SetBook(exchangeName, symbol, out)
+	if len(out.Bids) > 0 && len(out.Asks) > 0 {
+		f.cache.SetPrice(exchangeName, symbol, (out.Bids[0].Price+out.Asks[0].Price)/2, out.At)
+	}
+	f.bus.Publish(events.BookUpdate{Exchange: exchangeName, Symbol: symbol, Book: out})
+	return nil
+}
+
+func (f *Feed) resync(marketIndex uint16) {
+	channel := bookChannel(marketIndex)
+	if err := f.market.Unsubscribe(channel); err != nil {
+		log.Warn("failed to unsubscribe", "channel", channel, logger.Err(err))
+	}
+	if err := f.market.Subscribe(channel); err != nil {
+		log.Warn("failed to subscribe", "channel", channel, logger.Err(err))
+	}
+}
+
+// follows reports whether an update with offset and beginNonce continues
+// from the last message applied to the book. Nonces are only compared when
+// both are known.
+func (b *localBook) follows(offset, beginNonce int64) bool {
+	if offset <= b.offset {
+		return false
+	}
+	return beginNonce == 0 || b.nonce == 0 || beginNonce == b.nonce
+}
+
+func (b *localBook) apply(bids, asks []marketdata.Level) {
+	for _, side := range []struct {
+		levels map[float64]float64
+		update []marketdata.Level
+	}{{b.bids, bids}, {b.asks, asks}} {
+		for _, l := range side.update {
+			if l.Size == 0 {
+				delete(side.levels, l.Price)
+			} else {
+				side.levels[l.Price] = l.Size
+			}
+		}
+	}
+}
+
+func parseLevels(levels []wsLevel) ([]marketdata.Level, error) {
+	out := make([]marketdata.Level, 0, len(levels))
+	for _, l := range levels {
+		price, err := strconv.ParseFloat(l.Price, 64)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseFloat(l.Size, 64)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, marketdata.Level{Price: price, Size: size})
+	}
+	return out, nil
+}
+
+// topLevels returns up to bookLevels levels, best first.
+func topLevels(levels map[float64]float64, descending bool) []marketdata.Level {
+	out := make([]marketdata.Level, 0, len(levels))
+	for price, size := range levels {
+		out = append(out, marketdata.Level{Price: price, Size: size})
+	}
+	slices.SortFunc(out, func(a, b marketdata.Level) int {
+		if descending {
+			a, b = b, a
+		}
+		switch {
+		case a.Price < b.Price:
+			return -1
+		case a.Price > b.Price:
+			return 1
+		}
+		return 0
+	})
+	if len(out) > bookLevels {
+		out = out[:bookLevels]
+	}
+	return out
+}
+
+// onAccount publishes position changes and new trades. The snapshot's
+// trades happened before subscribing and are skipped.
+func (f *Feed) onAccount(venue string, account int64, snapshot bool, data []byte) error {
+	var msg wsAccountMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, p := range msg.Positions {
+		size, err := strconv.ParseFloat(p.Position, 64)
+		if err != nil {
+			return fmt.Errorf("position for %s: %w", p.Symbol, err)
+		}
+		if p.Sign < 0 {
+			size = -size
+		}
+		update := events.PositionUpdate{
+			Venue:    venue,
+			Position: exchange.Position{Symbol: p.Symbol + "-USD", Size: size},
+			Time:     now,
+		}
+		update.Position.EntryPrice, _ = strconv.ParseFloat(p.AvgEntryPrice, 64)
+		update.Position.UnrealizedPnL, _ = strconv.ParseFloat(p.UnrealizedPnl, 64)
+		f.bus.Publish(update)
+	}
+
+	if snapshot {
+		return nil
+	}
+	var errs []error
+	for _, trades := range msg.Trades {
+		for _, t := range trades {
+			// A bad trade is skipped, the others still count
+			if err := f.onTrade(venue, account, t); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f *Feed) onTrade(venue string, account int64, t wsTrade) error {
+	symbol, ok := marketSymbol(t.MarketId)
+	if !ok {
+		symbol = fmt.Sprintf("MARKET%d", t.MarketId)
+	}
+	size, err := strconv.ParseFloat(t.Size, 64)
+	if err != nil {
+		return fmt.Errorf("trade %d: invalid size: %w", t.TradeId, err)
+	}
+	price, err := strconv.ParseFloat(t.Price, 64)
+	if err != nil {
+		return fmt.Errorf("trade %d: invalid price: %w", t.TradeId, err)
+	}
+
+	// The account may be on either side, or both
+	for _, side := range []struct {
+		name     string
+		account  int64
+		orderID  int64
+		clientID int64
+		maker    bool
+	}{{"sell", t.AskAccountId, t.AskId, t.AskClientId, t.IsMakerAsk}, {"buy", t.BidAccountId, t.BidId, t.BidClientId, !t.IsMakerAsk}} {
+		if side.account != account {
+			continue
+		}
+		feeRate := t.TakerFee
+		if side.maker {
+			feeRate = t.MakerFee
+		}
+		f.bus.Publish(events.Fill{
+			Venue:   venue,
+			OrderID: orderID(side.orderID, side.clientID),
+			TradeID: strconv.FormatInt(t.TradeId, 10),
+			Symbol:  symbol + "-USD",
+			Side:    side.name,
+			Size:    size,
+			Price:   price,
+			Fee:     size * price * float64(feeRate) / float64(txtypes.FeeTick),
+			Time:    unixTime(t.Timestamp),
+		})
+	}
+	return nil
+}
+
+func (f *Feed) onOrders(venue string, data []byte) error {
+	var msg wsOrdersMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	for _, orders := range msg.Orders {
+		for _, o := range orders {
+			symbol, ok := marketSymbol(o.MarketIndex)
+			if !ok {
+				symbol = fmt.Sprintf("MARKET%d", o.MarketIndex)
+			}
+			side := "buy"
+			if o.IsAsk {
+				side = "sell"
+			}
+			update := events.OrderUpdate{
+				Venue:   venue,
+				OrderID: orderID(o.OrderIndex, o.ClientOrderIndex),
+				Symbol:  symbol + "-USD",
+				Side:    side,
+				Status:  o.Status,
+				Time:    unixTime(o.Timestamp),
+			}
+			update.Size, _ = strconv.ParseFloat(o.RemainingBaseAmount, 64)
+			update.Price, _ = strconv.ParseFloat(o.Price, 64)
+			f.bus.Publish(update)
+		}
+	}
+	return nil
+}
+
+// unixTime converts a Lighter timestamp, which some messages give in
+// seconds and others in milliseconds.
+func unixTime(ts int64) time.Time {
+	if ts > 1e12 {
+		return time.UnixMilli(ts)
+	}
+	return time.Unix(ts, 0)
+}
@@ -0,0 +1,31 @@
+package lighter
+
+import "testing"
+
+func TestLocalBookFollows(t *testing.T) {
+	tests := []struct {
+		name       string
+		offset     int64 // of the last message applied
+		nonce      int64
+		next       int64
+		beginNonce int64
+		want       bool
+	}{
+		{"next offset and nonce", 10, 500, 11, 500, true},
+		{"offset skips ahead", 10, 500, 15, 500, true},
+		{"repeated offset", 10, 500, 10, 500, false},
+		{"older offset", 10, 500, 9, 500, false},
+		{"nonce gap", 10, 500, 11, 520, false},
+		{"update without nonce", 10, 500, 11, 0, true},
+		{"snapshot without nonce", 10, 0, 11, 520, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &localBook{offset: tt.offset, nonce: tt.nonce}
+			if got := b.follows(tt.next, tt.beginNonce); got != tt.want {
+				t.Errorf("follows(%d, %d) after offset %d nonce %d = %v, want %v",
+					tt.next, tt.beginNonce, tt.offset, tt.nonce, got, tt.want)
+			}
+		})
+	}
+}
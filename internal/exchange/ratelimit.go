@@ -0,0 +1,139 @@
+package exchange
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/logger"
+)
+
+// budgetError is returned when a call would exceed the venue's request
+// budget for longer than it is allowed to wait. It is an ErrRateLimited.
+type budgetError struct {
+	venue string
+}
+
+func (e *budgetError) Error() string {
+	return fmt.Sprintf("rate limited: %s request budget exhausted", e.venue)
+}
+
+func (e *budgetError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Priority decides who gets the budget first when it runs low.
+type Priority int
+
+const (
+	// PriorityData is for market data and account queries.
+	PriorityData Priority = iota
+	// PriorityTrading is for placing and cancelling orders.
+	PriorityTrading
+)
+
+// Share of the budget market data may not use, so orders can still go out
+// while data polling is saturating the limit
+const tradingReserve = 0.2
+
+// How long a call may queue before it is rejected
+var maxWait = map[Priority]time.Duration{
+	PriorityData:    2 * time.Second,
+	PriorityTrading: 10 * time.Second,
+}
+
+// Limiter is a weighted token bucket modelling a venue's request budget
+// (e.g. Hyperliquid's 1200 weight per minute). A nil Limiter allows
+// everything. Adapters of the same venue should share one, since venues
+// count requests per IP.
+type Limiter struct {
+	venue    string
+	capacity float64
+	rate     float64 // weight per second
+
+	mu             sync.Mutex
+	tokens         float64
+	last           time.Time
+	tradingWaiting int
+}
+
+// NewLimiter creates a limiter allowing weightPerMinute, starting full.
+func NewLimiter(venue string, weightPerMinute int) *Limiter {
+	return &Limiter{
+		venue:    venue,
+		capacity: float64(weightPerMinute),
+		rate:     float64(weightPerMinute) / 60,
+		tokens:   float64(weightPerMinute),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until weight is available, or returns ErrRateLimited if that
+// would take longer than the priority's maximum wait. Trading calls are
+// served before data calls.
+func (l *Limiter) Wait(p Priority, weight int) error {
+	if l == nil || l.capacity <= 0 {
+		return nil
+	}
+
+	// A single call can't need more than the whole budget it may draw from
+	w := min(float64(weight), l.capacity)
+	if p == PriorityData {
+		w = min(w, l.capacity*(1-tradingReserve))
+	}
+	start := time.Now()
+	deadline := start.Add(maxWait[p])
+
+	if p == PriorityTrading {
+		l.mu.Lock()
+		l.tradingWaiting++
+		l.mu.Unlock()
+		defer func() {
+			l.mu.Lock()
+			l.tradingWaiting--
+			l.mu.Unlock()
+		}()
+	}
+
+	for {
+		delay, ok := l.take(p, w)
+		if ok {
+			if waited := time.Since(start); waited > 100*time.Millisecond {
+				log.Debug("request delayed by rate limit", logger.KeyVenue, l.venue, "weight", weight, "waited", waited)
+			}
+			return nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			log.Debug("request rejected by rate limit", logger.KeyVenue, l.venue, "weight", weight)
+			return &budgetError{venue: l.venue}
+		}
+		time.Sleep(delay)
+	}
+}
+
+// take consumes w if allowed, otherwise returns how long to wait before
+// trying again.
+func (l *Limiter) take(p Priority, w float64) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.capacity, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	floor := 0.0
+	if p == PriorityData {
+		// Queued orders go first
+		if l.tradingWaiting > 0 {
+			return 50 * time.Millisecond, false
+		}
+		floor = l.capacity * tradingReserve
+	}
+
+	if l.tokens-w >= floor {
+		l.tokens -= w
+		return 0, true
+	}
+	need := w + floor - l.tokens
+	return time.Duration(need / l.rate * float64(time.Second)), false
+}
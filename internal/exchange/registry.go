@@ -0,0 +1,240 @@
+package exchange
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/logger"
+)
+
+var log = logger.For("exchange")
+
+// Factory constructs an adapter.
+type Factory func() Exchange
+
+// Checker is implemented by adapters that need setup (metadata, auth)
+// before they can be used. CheckReady retries whatever failed and returns
+// nil once the adapter is usable.
+type Checker interface {
+	CheckReady() error
+}
+
+// VenueStatus is the readiness of one venue.
+type VenueStatus struct {
+	Ready     bool         `json:"ready"`
+	Error     string       `json:"error,omitempty"`
+	CheckedAt time.Time    `json:"checked_at"`
+	Breaker   BreakerState `json:"breaker"`
+}
+
+type venue struct {
+	raw     Exchange // unwrapped, for readiness checks
+	exc     Exchange
+	breaker *Breaker // nil if breakers are disabled
+	VenueStatus
+}
+
+// usable reports whether the venue is ready and its breaker lets calls in.
+func (v *venue) usable() bool {
+	return v.Ready && (v.breaker == nil || v.breaker.Allow())
+}
+
+// Registry builds the enabled adapters and tracks which of them are ready.
+// Strategies should only trade on Ready venues.
+type Registry struct {
+	wrap func(name string, exc Exchange) Exchange
+
+	// OnStatusChange, if set, is called with each venue's initial status
+	// and whenever it becomes ready or stops being ready. Set it before
+	// Register.
+	OnStatusChange func(name string, status VenueStatus)
+
+	// Circuit breaker settings, applied by Register. BreakerFailures 0
+	// disables breakers.
+	BreakerFailures int
+	BreakerCooldown time.Duration
+	OnBreakerChange func(name string, state BreakerState)
+
+	mu     sync.RWMutex
+	venues map[string]*venue
+}
+
+// NewRegistry creates a registry. wrap, if not nil, decorates each adapter
+// after construction (e.g. with metrics).
+func NewRegistry(wrap func(name string, exc Exchange) Exchange) *Registry {
+	return &Registry{
+		wrap:   wrap,
+		venues: make(map[string]*venue),
+	}
+}
+
+// Register builds the adapter if enabled and checks its readiness.
+func (r *Registry) Register(name string, enabled bool, factory Factory) {
+	if !enabled {
+		log.Info("exchange disabled", logger.KeyVenue, name)
+		return
+	}
+
+	raw := factory()
+	v := &venue{raw: raw, exc: raw}
+	if r.BreakerFailures > 0 {
+		v.breaker = NewBreaker(name, raw, r.BreakerFailures, r.BreakerCooldown)
+		v.breaker.OnChange = r.OnBreakerChange
+		v.exc = v.breaker
+	}
+	if r.wrap != nil {
+		v.exc = r.wrap(name, v.exc)
+	}
+	v.VenueStatus = check(raw)
+	if v.Ready {
+		log.Info("exchange ready", logger.KeyVenue, name)
+	} else {
+		log.Warn("exchange not ready, excluded until it recovers", logger.KeyVenue, name, "reason", v.Error)
+	}
+
+	r.mu.Lock()
+	r.venues[name] = v
+	r.mu.Unlock()
+
+	if r.OnStatusChange != nil {
+		r.OnStatusChange(name, v.VenueStatus)
+	}
+}
+
+func check(raw Exchange) VenueStatus {
+	st := VenueStatus{Ready: true, CheckedAt: time.Now()}
+	if c, ok := raw.(Checker); ok {
+		if err := c.CheckReady(); err != nil {
+			st.Ready = false
+			st.Error = err.Error()
+		}
+	}
+	return st
+}
+
+// All returns every built venue, ready or not.
+func (r *Registry) All() map[string]Exchange {
+	return r.filter(false)
+}
+
+// Ready returns the venues that are currently usable: ready, and not cooling
+// down behind an open breaker.
+func (r *Registry) Ready() map[string]Exchange {
+	return r.filter(true)
+}
+
+func (r *Registry) filter(readyOnly bool) map[string]Exchange {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]Exchange, len(r.venues))
+	for name, v := range r.venues {
+		if readyOnly && !v.usable() {
+			continue
+		}
+		out[name] = v.exc
+	}
+	return out
+}
+
+// Get returns a built venue, ready or not.
+func (r *Registry) Get(name string) (Exchange, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.venues[name]
+	if !ok {
+		return nil, false
+	}
+	return v.exc, true
+}
+
+// IsReady reports whether name is built and usable (see Ready).
+func (r *Registry) IsReady(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.venues[name]
+	return ok && v.usable()
+}
+
+// Status returns the readiness and breaker state of every built venue.
+func (r *Registry) Status() map[string]VenueStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]VenueStatus, len(r.venues))
+	for name, v := range r.venues {
+		st := v.VenueStatus
+		if v.breaker != nil {
+			st.Breaker = v.breaker.State()
+		}
+		out[name] = st
+	}
+	return out
+}
+
+// Names returns the built venue names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.venues))
+	for name := range r.venues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Watch rechecks every venue each interval until ctx is cancelled, taking
+// venues whose check fails out of rotation and bringing them back once it
+// passes.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.recheck()
+		}
+	}
+}
+
+func (r *Registry) recheck() {
+	r.mu.RLock()
+	venues := make(map[string]*venue, len(r.venues))
+	for name, v := range r.venues {
+		venues[name] = v
+	}
+	r.mu.RUnlock()
+
+	for name, v := range venues {
+		st := check(v.raw)
+
+		r.mu.Lock()
+		wasReady := v.Ready
+		v.VenueStatus = st
+		r.mu.Unlock()
+
+		switch {
+		case st.Ready == wasReady:
+			if !st.Ready {
+				log.Debug("exchange still not ready", logger.KeyVenue, name, "reason", st.Error)
+			}
+			continue
+		case st.Ready:
+			log.Info("exchange recovered, back in rotation", logger.KeyVenue, name)
+		default:
+			log.Warn("exchange no longer ready, excluded until it recovers", logger.KeyVenue, name, "reason", st.Error)
+		}
+		if r.OnStatusChange != nil {
+			r.OnStatusChange(name, st)
+		}
+	}
+}
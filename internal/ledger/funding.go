@@ -0,0 +1,205 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+)
+
+var log = logger.For("ledger")
+
+// Attributor links funding payments to arb pairs and to the funding rates
+// the strategy was acting on.
+type Attributor interface {
+	// PairFor returns the arb pair that held symbol on an exchange at the
+	// given time, or "".
+	PairFor(exchangeName, symbol string, at time.Time) string
+	// LatestRate returns the last funding rate observed for symbol on an exchange.
+	LatestRate(exchangeName, symbol string) (float64, bool)
+}
+
+// FundingCollector periodically pulls funding payments from each exchange
+// and stores them in the journal, alongside the amount we expected to
+// receive given the rate the strategy observed.
+type FundingCollector struct {
+	ledger     *Ledger
+	exchanges  map[string]exchange.Exchange
+	interval   time.Duration
+	attributor Attributor // may be nil
+
+	mu   sync.Mutex
+	seen map[string]bool // exchange/payment ID
+	last map[string]time.Time
+	skip map[string]bool // exchanges that can't report payments
+}
+
+// Lookback for exchanges with no funding in the journal yet
+const initialFundingLookback = 24 * time.Hour
+
+func NewFundingCollector(ldg *Ledger, exchanges map[string]exchange.Exchange, interval time.Duration, attributor Attributor) *FundingCollector {
+	c := &FundingCollector{
+		ledger:     ldg,
+		exchanges:  exchanges,
+		interval:   interval,
+		attributor: attributor,
+		seen:       make(map[string]bool),
+		last:       make(map[string]time.Time),
+		skip:       make(map[string]bool),
+	}
+
+	// Resume from what is already journaled
+	for _, e := range ldg.Entries() {
+		if e.Kind != KindFunding || e.Ref == "" {
+			continue
+		}
+		c.seen[e.Exchange+"/"+e.Ref] = true
+		if e.Time.After(c.last[e.Exchange]) {
+			c.last[e.Exchange] = e.Time
+		}
+	}
+	return c
+}
+
+func (c *FundingCollector) Start(ctx context.Context) {
+	log.Info("starting funding collector")
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.Collect()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("stopping funding collector")
+			return
+		case <-ticker.C:
+			c.Collect()
+		}
+	}
+}
+
+// Collect fetches new funding payments from every exchange.
+func (c *FundingCollector) Collect() {
+	for name, exc := range c.exchanges {
+		c.mu.Lock()
+		since, ok := c.last[name]
+		skip := c.skip[name]
+		c.mu.Unlock()
+		if skip {
+			continue
+		}
+		if !ok {
+			since = time.Now().Add(-initialFundingLookback)
+		}
+
+		payments, err := exc.GetFundingPayments(since)
+		if errors.Is(err, exchange.ErrNotImplemented) {
+			// Won't change until a restart, so say it once
+			log.Warn("exchange can't report funding payments, skipping it", logger.KeyVenue, name, logger.Err(err))
+			c.mu.Lock()
+			c.skip[name] = true
+			c.mu.Unlock()
+			continue
+		}
+		if err != nil {
+			log.Warn("failed to get funding payments", logger.KeyVenue, name, logger.Err(err))
+			continue
+		}
+
+		sort.Slice(payments, func(i, j int) bool { return payments[i].Time.Before(payments[j].Time) })
+		for _, p := range payments {
+			if err := c.record(name, p); err != nil {
+				log.Error("failed to record funding payment", logger.KeyVenue, name, "payment_id", p.ID, logger.Err(err))
+			}
+		}
+	}
+}
+
+func (c *FundingCollector) record(exchangeName string, p *exchange.FundingPayment) error {
+	key := exchangeName + "/" + p.ID
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seen[key] {
+		return nil
+	}
+
+	entry := Entry{
+		Time:     p.Time,
+		Kind:     KindFunding,
+		Exchange: exchangeName,
+		Symbol:   p.Symbol,
+		Size:     p.Size,
+		Amount:   p.Amount,
+		Ref:      p.ID,
+		Rate:     p.Rate,
+	}
+	if c.attributor != nil {
+		entry.PairID = c.attributor.PairFor(exchangeName, p.Symbol, p.Time)
+		if observed, ok := c.attributor.LatestRate(exchangeName, p.Symbol); ok && p.Rate != 0 {
+			// Amount = -rate * notional, so the same notional at the
+			// observed rate would have paid Amount * observed / rate
+			entry.Expected = p.Amount * observed / p.Rate
+		}
+	}
+
+	if err := c.ledger.Record(entry); err != nil {
+		return err
+	}
+	c.seen[key] = true
+	if p.Time.After(c.last[exchangeName]) {
+		c.last[exchangeName] = p.Time
+	}
+	return nil
+}
+
+// FundingComparison compares funding actually received with what the
+// observed rates implied, per pair leg.
+type FundingComparison struct {
+	Key
+	Payments int     `json:"payments"`
+	Actual   float64 `json:"actual"`
+	Expected float64 `json:"expected"`
+	Diff     float64 `json:"diff"` // actual - expected
+}
+
+// CompareFunding summarizes funding payments since the given time.
+func (l *Ledger) CompareFunding(since time.Time) []FundingComparison {
+	byKey := make(map[Key]*FundingComparison)
+	for _, e := range l.Entries() {
+		if e.Kind != KindFunding || e.Time.Before(since) {
+			continue
+		}
+		key := Key{PairID: e.PairID, Exchange: e.Exchange, Symbol: e.Symbol}
+		fc, ok := byKey[key]
+		if !ok {
+			fc = &FundingComparison{Key: key}
+			byKey[key] = fc
+		}
+		fc.Payments++
+		fc.Actual += e.Amount
+		fc.Expected += e.Expected
+		fc.Diff = fc.Actual - fc.Expected
+	}
+
+	result := make([]FundingComparison, 0, len(byKey))
+	for _, fc := range byKey {
+		result = append(result, *fc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.PairID != b.PairID {
+			return a.PairID < b.PairID
+		}
+		if a.Exchange != b.Exchange {
+			return a.Exchange < b.Exchange
+		}
+		return a.Symbol < b.Symbol
+	})
+	return result
+}
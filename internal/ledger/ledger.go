@@ -0,0 +1,291 @@
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/events"
+)
+
+// Entry kinds
+const (
+	KindTrade   = "trade"
+	KindFee     = "fee"
+	KindFunding = "funding"
+)
+
+// Entry is one line of the journal. Amount is signed from our point of
+// view: fees paid are negative, funding received is positive.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"`
+	PairID   string    `json:"pair_id,omitempty"`
+	Exchange string    `json:"exchange"`
+	Symbol   string    `json:"symbol"`
+	Side     string    `json:"side,omitempty"` // trades only: "buy" or "sell"
+	Size     float64   `json:"size,omitempty"`
+	Price    float64   `json:"price,omitempty"`
+	Amount   float64   `json:"amount,omitempty"`
+	OrderID  string    `json:"order_id,omitempty"`
+	Ref      string    `json:"ref,omitempty"` // venue payment or trade ID
+
+	// Funding only
+	Rate     float64 `json:"rate,omitempty"` // rate the venue applied
+	Expected float64 `json:"expected,omitempty"`
+}
+
+// Ledger is an append-only journal of trades, fees and funding, attributed
+// to arb pairs and venues. Entries are kept in memory and appended to a
+// JSON-lines file.
+type Ledger struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries []Entry
+
+	orders map[string]*orderFills // by exchange/order ID
+	trades map[string]bool        // exchange/trade IDs booked from fill streams
+}
+
+// orderFills is how much of an order was reported filled by its placement
+// response and by the venue's fill stream. Both report the same executions,
+// in either order, so only the larger of the two is booked.
+type orderFills struct {
+	placed   float64
+	streamed float64
+}
+
+// add counts size more from one source and returns how much of it wasn't
+// booked yet.
+func (o *orderFills) add(source *float64, size float64) float64 {
+	before := max(o.placed, o.streamed)
+	*source += size
+	return max(o.placed, o.streamed) - before
+}
+
+// Open loads the journal at path, creating it if needed.
+func Open(path string) (*Ledger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create journal dir: %w", err)
+	}
+
+	entries, err := readJournal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	l := &Ledger{
+		file:    file,
+		entries: entries,
+		orders:  make(map[string]*orderFills),
+		trades:  make(map[string]bool),
+	}
+	for _, e := range entries {
+		if e.Kind != KindTrade {
+			continue
+		}
+		// Only what was booked is journaled, so each source is rebuilt
+		// from the size it booked: stream fills carry the trade ID
+		if e.Ref != "" {
+			l.trades[e.Exchange+"/"+e.Ref] = true
+		}
+		if e.OrderID != "" {
+			o := l.order(e.Exchange, e.OrderID)
+			if e.Ref != "" {
+				o.streamed += e.Size
+			} else {
+				o.placed += e.Size
+			}
+		}
+	}
+	return l, nil
+}
+
+func readJournal(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("journal %s line %d: %w", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Record appends an entry to the journal.
+func (l *Ledger) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	l.entries = append(l.entries, e)
+	return nil
+}
+
+// RecordTrade records the part of an order that filled on placement, and
+// the fee paid for it if any. Whatever the venue's fill stream already
+// booked for the order is left out. Returns the size booked.
+func (l *Ledger) RecordTrade(pairID, exchangeName, symbol, side string, size, price, fee float64, orderID string) (float64, error) {
+	l.mu.Lock()
+	o := l.order(exchangeName, orderID)
+	booked := o.add(&o.placed, size)
+	l.mu.Unlock()
+
+	err := l.recordTrade(Entry{
+		Kind:     KindTrade,
+		PairID:   pairID,
+		Exchange: exchangeName,
+		Symbol:   symbol,
+		Side:     side,
+		Size:     booked,
+		Price:    price,
+		OrderID:  orderID,
+	}, fee*booked/size)
+	if err != nil {
+		l.mu.Lock()
+		o.placed -= size
+		l.mu.Unlock()
+		return 0, err
+	}
+	return booked, nil
+}
+
+// RecordFill records a fill pushed by a venue's fill stream, and the fee
+// paid for it if any. Trades already booked and whatever the order's
+// placement response already booked are left out. Returns the size booked.
+func (l *Ledger) RecordFill(pairID string, f events.Fill) (float64, error) {
+	key := f.Venue + "/" + f.TradeID
+	l.mu.Lock()
+	if f.TradeID != "" && l.trades[key] {
+		l.mu.Unlock()
+		return 0, nil
+	}
+	o := l.order(f.Venue, f.OrderID)
+	booked := o.add(&o.streamed, f.Size)
+	l.mu.Unlock()
+
+	err := l.recordTrade(Entry{
+		Time:     f.Time,
+		Kind:     KindTrade,
+		PairID:   pairID,
+		Exchange: f.Venue,
+		Symbol:   f.Symbol,
+		Side:     f.Side,
+		Size:     booked,
+		Price:    f.Price,
+		OrderID:  f.OrderID,
+		Ref:      f.TradeID,
+	}, f.Fee*booked/f.Size)
+
+	// The trade is only marked booked once it's in the journal, so a
+	// failed write is retried if the venue sends the fill again
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err != nil {
+		o.streamed -= f.Size
+		return 0, err
+	}
+	if f.TradeID != "" {
+		l.trades[key] = true
+	}
+	return booked, nil
+}
+
+// order returns the fills of an order, l.mu held.
+func (l *Ledger) order(exchangeName, orderID string) *orderFills {
+	key := exchangeName + "/" + orderID
+	o := l.orders[key]
+	if o == nil {
+		o = &orderFills{}
+		l.orders[key] = o
+	}
+	return o
+}
+
+// recordTrade appends trade e, if any size is left of it, and the fee paid
+// for it.
+func (l *Ledger) recordTrade(e Entry, fee float64) error {
+	if e.Size <= 0 {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if err := l.Record(e); err != nil || fee == 0 {
+		return err
+	}
+
+	return l.Record(Entry{
+		Time:     e.Time,
+		Kind:     KindFee,
+		PairID:   e.PairID,
+		Exchange: e.Exchange,
+		Symbol:   e.Symbol,
+		Amount:   -fee,
+		OrderID:  e.OrderID,
+		Ref:      e.Ref,
+	})
+}
+
+// Entries returns a copy of all journal entries.
+func (l *Ledger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]Entry(nil), l.entries...)
+}
+
+// Sync flushes the journal to disk.
+func (l *Ledger) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Sync()
+}
+
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	return l.file.Close()
+}
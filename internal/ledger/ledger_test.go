@@ -0,0 +1,52 @@
+package ledger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"arbitrage-bot/internal/events"
+)
+
+func TestReopenKeepsFills(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.RecordTrade("p1", "lighter", "ETH-USD", "buy", 1, 100, 0, "7"); err != nil {
+		t.Fatal(err)
+	}
+	fill := events.Fill{Venue: "lighter", OrderID: "8", TradeID: "t1", Symbol: "ETH-USD", Side: "sell", Size: 2, Price: 100, Time: time.Now()}
+	if _, err := l.RecordFill("p1", fill); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	l, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	tests := []struct {
+		name string
+		fill events.Fill
+		want float64
+	}{
+		{"trade booked before the restart", fill, 0},
+		{"stream catching up with the placement", events.Fill{Venue: "lighter", OrderID: "7", TradeID: "t2", Size: 1, Price: 100}, 0},
+		{"new trade of a placed order", events.Fill{Venue: "lighter", OrderID: "7", TradeID: "t3", Size: 0.5, Price: 100}, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booked, err := l.RecordFill("p1", tt.fill)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if booked != tt.want {
+				t.Errorf("booked %g, want %g", booked, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package ledger
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MarkFunc returns the current mark price of symbol on an exchange.
+type MarkFunc func(exchangeName, symbol string) (float64, bool)
+
+// Key identifies one leg of an arb pair: a symbol on one venue.
+type Key struct {
+	PairID   string `json:"pair_id"`
+	Exchange string `json:"exchange"`
+	Symbol   string `json:"symbol"`
+}
+
+// DailySummary is the PnL of one pair leg over one UTC day.
+type DailySummary struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Key
+	TradingPnL float64 `json:"trading_pnl"` // realized
+	Fees       float64 `json:"fees"`
+	Funding    float64 `json:"funding"`
+	Net        float64 `json:"net"`
+}
+
+// LegPnL is the all-time PnL of one pair leg, marked to market.
+type LegPnL struct {
+	Key
+	Size       float64 `json:"size"` // signed, positive for long
+	AvgEntry   float64 `json:"avg_entry"`
+	Mark       float64 `json:"mark"`
+	TradingPnL float64 `json:"trading_pnl"` // realized
+	Unrealized float64 `json:"unrealized"`
+	Fees       float64 `json:"fees"`
+	Funding    float64 `json:"funding"`
+	Net        float64 `json:"net"`
+}
+
+// book tracks an average-cost position while replaying trades.
+type book struct {
+	size     float64
+	avgEntry float64
+}
+
+// apply books a trade and returns the PnL it realized.
+func (b *book) apply(side string, size, price float64) float64 {
+	qty := size
+	if side == "sell" {
+		qty = -size
+	}
+
+	// Opening or adding to a position
+	if b.size == 0 || (b.size > 0) == (qty > 0) {
+		total := math.Abs(b.size) + math.Abs(qty)
+		b.avgEntry = (b.avgEntry*math.Abs(b.size) + price*math.Abs(qty)) / total
+		b.size += qty
+		return 0
+	}
+
+	// Reducing, closing or flipping
+	closed := math.Min(math.Abs(qty), math.Abs(b.size))
+	realized := closed * (price - b.avgEntry)
+	if b.size < 0 {
+		realized = -realized
+	}
+
+	flipped := math.Abs(qty) > math.Abs(b.size)
+	b.size += qty
+	switch {
+	case flipped:
+		b.avgEntry = price
+	case b.size == 0:
+		b.avgEntry = 0
+	}
+	return realized
+}
+
+// Daily summarizes realized trading PnL, fees and funding per day and pair
+// leg, for days starting at or after since.
+func (l *Ledger) Daily(since time.Time) []DailySummary {
+	books := make(map[Key]*book)
+	days := make(map[string]*DailySummary)
+
+	for _, e := range l.Entries() {
+		key := Key{PairID: e.PairID, Exchange: e.Exchange, Symbol: e.Symbol}
+
+		var realized float64
+		if e.Kind == KindTrade {
+			b, ok := books[key]
+			if !ok {
+				b = &book{}
+				books[key] = b
+			}
+			// Trades before the window still move the book
+			realized = b.apply(e.Side, e.Size, e.Price)
+		}
+
+		if e.Time.Before(since) {
+			continue
+		}
+
+		date := e.Time.UTC().Format("2006-01-02")
+		id := date + "|" + key.PairID + "|" + key.Exchange + "|" + key.Symbol
+		d, ok := days[id]
+		if !ok {
+			d = &DailySummary{Date: date, Key: key}
+			days[id] = d
+		}
+
+		switch e.Kind {
+		case KindTrade:
+			d.TradingPnL += realized
+		case KindFee:
+			d.Fees += e.Amount
+		case KindFunding:
+			d.Funding += e.Amount
+		}
+		d.Net = d.TradingPnL + d.Fees + d.Funding
+	}
+
+	result := make([]DailySummary, 0, len(days))
+	for _, d := range days {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.PairID != b.PairID {
+			return a.PairID < b.PairID
+		}
+		return a.Exchange < b.Exchange
+	})
+	return result
+}
+
+// Legs returns the all-time PnL of every pair leg. Open positions are
+// marked to market with marks; legs without a mark have zero unrealized PnL.
+func (l *Ledger) Legs(marks MarkFunc) []LegPnL {
+	books := make(map[Key]*book)
+	legs := make(map[Key]*LegPnL)
+
+	for _, e := range l.Entries() {
+		key := Key{PairID: e.PairID, Exchange: e.Exchange, Symbol: e.Symbol}
+		leg, ok := legs[key]
+		if !ok {
+			leg = &LegPnL{Key: key}
+			legs[key] = leg
+			books[key] = &book{}
+		}
+
+		switch e.Kind {
+		case KindTrade:
+			leg.TradingPnL += books[key].apply(e.Side, e.Size, e.Price)
+		case KindFee:
+			leg.Fees += e.Amount
+		case KindFunding:
+			leg.Funding += e.Amount
+		}
+	}
+
+	result := make([]LegPnL, 0, len(legs))
+	for key, leg := range legs {
+		b := books[key]
+		leg.Size = b.size
+		leg.AvgEntry = b.avgEntry
+		if b.size != 0 && marks != nil {
+			if mark, ok := marks(key.Exchange, key.Symbol); ok {
+				leg.Mark = mark
+				leg.Unrealized = b.size * (mark - b.avgEntry)
+			}
+		}
+		leg.Net = leg.TradingPnL + leg.Unrealized + leg.Fees + leg.Funding
+		result = append(result, *leg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.PairID != b.PairID {
+			return a.PairID < b.PairID
+		}
+		return a.Exchange < b.Exchange
+	})
+	return result
+}
@@ -0,0 +1,142 @@
+package ledger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type trade struct {
+	side        string
+	size, price float64
+}
+
+func TestBookApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		trades   []trade
+		realized []float64 // per trade
+		size     float64
+		avgEntry float64
+	}{
+		{
+			name:     "open long",
+			trades:   []trade{{"buy", 2, 100}},
+			realized: []float64{0},
+			size:     2, avgEntry: 100,
+		},
+		{
+			name:     "add to long averages the entry",
+			trades:   []trade{{"buy", 1, 100}, {"buy", 3, 104}},
+			realized: []float64{0, 0},
+			size:     4, avgEntry: 103,
+		},
+		{
+			name:     "partial close of long at a profit",
+			trades:   []trade{{"buy", 2, 100}, {"sell", 1, 110}},
+			realized: []float64{0, 10},
+			size:     1, avgEntry: 100,
+		},
+		{
+			name:     "full close resets the entry",
+			trades:   []trade{{"buy", 2, 100}, {"sell", 2, 95}},
+			realized: []float64{0, -10},
+			size:     0, avgEntry: 0,
+		},
+		{
+			name:     "cover short at a loss",
+			trades:   []trade{{"sell", 3, 100}, {"buy", 3, 102}},
+			realized: []float64{0, -6},
+			size:     0, avgEntry: 0,
+		},
+		{
+			name:     "cover short at a profit",
+			trades:   []trade{{"sell", 2, 100}, {"sell", 2, 90}, {"buy", 1, 80}},
+			realized: []float64{0, 0, 15},
+			size:     -3, avgEntry: 95,
+		},
+		{
+			name:     "flip long to short opens at the flip price",
+			trades:   []trade{{"buy", 1, 100}, {"sell", 3, 120}},
+			realized: []float64{0, 20},
+			size:     -2, avgEntry: 120,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b book
+			for i, tr := range tt.trades {
+				if got := b.apply(tr.side, tr.size, tr.price); got != tt.realized[i] {
+					t.Errorf("trade %d realized %g, want %g", i, got, tt.realized[i])
+				}
+			}
+			if b.size != tt.size || b.avgEntry != tt.avgEntry {
+				t.Errorf("size %g avg entry %g, want size %g avg entry %g", b.size, b.avgEntry, tt.size, tt.avgEntry)
+			}
+		})
+	}
+}
+
+func TestLegs(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	at := time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{Time: at, Kind: KindTrade, PairID: "p1", Exchange: "hyperliquid", Symbol: "ETH-USD", Side: "buy", Size: 2, Price: 100},
+		{Time: at, Kind: KindFee, PairID: "p1", Exchange: "hyperliquid", Symbol: "ETH-USD", Amount: -0.1},
+		{Time: at, Kind: KindTrade, PairID: "p1", Exchange: "lighter", Symbol: "ETH-USD", Side: "sell", Size: 2, Price: 101},
+		{Time: at.Add(time.Hour), Kind: KindFunding, PairID: "p1", Exchange: "lighter", Symbol: "ETH-USD", Amount: 0.5},
+		{Time: at.Add(2 * time.Hour), Kind: KindTrade, PairID: "p1", Exchange: "hyperliquid", Symbol: "ETH-USD", Side: "sell", Size: 1, Price: 104},
+	} {
+		if err := l.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	marks := func(exchangeName, symbol string) (float64, bool) {
+		mark, ok := map[string]float64{"hyperliquid": 105, "lighter": 99}[exchangeName]
+		return mark, ok
+	}
+
+	tests := []struct {
+		exchange   string
+		size       float64
+		avgEntry   float64
+		trading    float64
+		unrealized float64
+		fees       float64
+		funding    float64
+	}{
+		{"hyperliquid", 1, 100, 4, 5, -0.1, 0},
+		{"lighter", -2, 101, 0, 4, 0, 0.5},
+	}
+
+	legs := l.Legs(marks)
+	if len(legs) != len(tests) {
+		t.Fatalf("got %d legs, want %d: %+v", len(legs), len(tests), legs)
+	}
+	for i, tt := range tests {
+		t.Run(tt.exchange, func(t *testing.T) {
+			leg := legs[i]
+			if leg.Exchange != tt.exchange {
+				t.Fatalf("leg %d is %s, want %s", i, leg.Exchange, tt.exchange)
+			}
+			if leg.Size != tt.size || leg.AvgEntry != tt.avgEntry {
+				t.Errorf("size %g avg entry %g, want %g and %g", leg.Size, leg.AvgEntry, tt.size, tt.avgEntry)
+			}
+			if leg.TradingPnL != tt.trading || leg.Unrealized != tt.unrealized {
+				t.Errorf("trading %g unrealized %g, want %g and %g", leg.TradingPnL, leg.Unrealized, tt.trading, tt.unrealized)
+			}
+			if leg.Fees != tt.fees || leg.Funding != tt.funding {
+				t.Errorf("fees %g funding %g, want %g and %g", leg.Fees, leg.Funding, tt.fees, tt.funding)
+			}
+			if want := tt.trading + tt.unrealized + tt.fees + tt.funding; leg.Net != want {
+				t.Errorf("net %g, want %g", leg.Net, want)
+			}
+		})
+	}
+}
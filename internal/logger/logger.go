@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Common field keys
+const (
+	KeyModule        = "module"
+	KeyStrategy      = "strategy"
+	KeyVenue         = "venue"
+	KeySymbol        = "symbol"
+	KeyOrderID       = "order_id"
+	KeyCorrelationID = "correlation_id"
+	KeyError         = "error"
+)
+
+// sinkConfig is the active output and level configuration. Loggers resolve
+// it on every call, so package-level loggers created before Init pick up
+// the configured format and levels.
+type sinkConfig struct {
+	handler slog.Handler
+	level   slog.Level
+	modules map[string]slog.Level
+}
+
+var active atomic.Pointer[sinkConfig]
+
+func init() {
+	active.Store(&sinkConfig{
+		handler: newSink(os.Stderr, "console"),
+		level:   slog.LevelInfo,
+	})
+	slog.SetDefault(slog.New(&handler{}))
+}
+
+// Init configures the output format ("console" or "json"), the global
+// level and per-module level overrides.
+func Init(format, level string, moduleLevels map[string]string) error {
+	if format == "" {
+		format = "console"
+	}
+	if format != "console" && format != "json" {
+		return fmt.Errorf("unknown log format %q (want console or json)", format)
+	}
+
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	modules := make(map[string]slog.Level, len(moduleLevels))
+	for module, l := range moduleLevels {
+		ml, err := ParseLevel(l)
+		if err != nil {
+			return fmt.Errorf("log level for module %s: %w", module, err)
+		}
+		modules[strings.ToLower(module)] = ml
+	}
+
+	active.Store(&sinkConfig{
+		handler: newSink(os.Stderr, format),
+		level:   lvl,
+		modules: modules,
+	})
+	return nil
+}
+
+// ParseLevel parses debug, info, warn or error. Empty means info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+func newSink(w io.Writer, format string) slog.Handler {
+	// Levels are filtered by handler.Enabled, so the sink accepts everything
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if format == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// For returns the logger for a module. Its level can be overridden with
+// app.log_levels.<module>.
+func For(module string) *slog.Logger {
+	return slog.New(&handler{}).With(KeyModule, module)
+}
+
+// Err is shorthand for the error attribute.
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
+
+// NewCorrelationID returns a short random ID to tie together the log lines
+// of one operation, e.g. both legs of an arb execution.
+func NewCorrelationID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// handler applies per-module levels and forwards records to the active sink.
+type handler struct {
+	module string
+	ops    []func(slog.Handler) slog.Handler // WithAttrs/WithGroup calls, replayed on the sink
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	cfg := active.Load()
+	min := cfg.level
+	if ml, ok := cfg.modules[h.module]; ok {
+		min = ml
+	}
+	return level >= min
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	sink := active.Load().handler
+	for _, op := range h.ops {
+		sink = op(sink)
+	}
+	return sink.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := h.clone()
+	for _, a := range attrs {
+		if a.Key == KeyModule {
+			nh.module = strings.ToLower(a.Value.String())
+		}
+	}
+	nh.ops = append(nh.ops, func(s slog.Handler) slog.Handler { return s.WithAttrs(attrs) })
+	return nh
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	nh := h.clone()
+	nh.ops = append(nh.ops, func(s slog.Handler) slog.Handler { return s.WithGroup(name) })
+	return nh
+}
+
+func (h *handler) clone() *handler {
+	return &handler{
+		module: h.module,
+		ops:    append([]func(slog.Handler) slog.Handler(nil), h.ops...),
+	}
+}
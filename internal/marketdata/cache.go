@@ -0,0 +1,142 @@
+package marketdata
+
+import (
+	"sync"
+	"time"
+)
+
+// Level is one price level of an order book.
+type Level struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// Book is an order book, bids best (highest) first and asks best (lowest)
+// first.
+type Book struct {
+	Bids []Level   `json:"bids"`
+	Asks []Level   `json:"asks"`
+	At   time.Time `json:"at"`
+}
+
+type key struct {
+	exchange string
+	symbol   string
+}
+
+type value struct {
+	v  float64
+	at time.Time
+}
+
+// Cache holds the latest market data pushed by the venues' WebSocket feeds,
+// by exchange (not account) and pair. Entries older than the max age are
+// treated as missing, so adapters fall back to REST when a feed stalls. A
+// nil *Cache is empty.
+type Cache struct {
+	maxAge time.Duration
+
+	mu      sync.RWMutex
+	prices  map[key]value
+	funding map[key]value
+	books   map[key]Book
+}
+
+// NewCache creates a cache whose entries are fresh for maxAge.
+func NewCache(maxAge time.Duration) *Cache {
+	return &Cache{
+		maxAge:  maxAge,
+		prices:  make(map[key]value),
+		funding: make(map[key]value),
+		books:   make(map[key]Book),
+	}
+}
+
+func (c *Cache) SetPrice(exchange, symbol string, price float64, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prices[key{exchange, symbol}] = value{price, at}
+}
+
+func (c *Cache) SetFundingRate(exchange, symbol string, rate float64, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.funding[key{exchange, symbol}] = value{rate, at}
+}
+
+func (c *Cache) SetBook(exchange, symbol string, book Book) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.books[key{exchange, symbol}] = book
+}
+
+// Clear drops everything known about exchange, e.g. when its feed
+// disconnects.
+func (c *Cache) Clear(exchange string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, m := range []map[key]value{c.prices, c.funding} {
+		for k := range m {
+			if k.exchange == exchange {
+				delete(m, k)
+			}
+		}
+	}
+	for k := range c.books {
+		if k.exchange == exchange {
+			delete(c.books, k)
+		}
+	}
+}
+
+// Prices returns the fresh prices of symbols on exchange, and the symbols
+// it has none for.
+func (c *Cache) Prices(exchange string, symbols []string) (map[string]float64, []string) {
+	if c == nil {
+		return map[string]float64{}, symbols
+	}
+	return c.lookup(c.prices, exchange, symbols)
+}
+
+// FundingRates returns the fresh funding rates of symbols on exchange, and
+// the symbols it has none for.
+func (c *Cache) FundingRates(exchange string, symbols []string) (map[string]float64, []string) {
+	if c == nil {
+		return map[string]float64{}, symbols
+	}
+	return c.lookup(c.funding, exchange, symbols)
+}
+
+// Book returns the order book of symbol on exchange if it is fresh.
+func (c *Cache) Book(exchange, symbol string) (Book, bool) {
+	if c == nil {
+		return Book{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	book, ok := c.books[key{exchange, symbol}]
+	if !ok || time.Since(book.At) > c.maxAge {
+		return Book{}, false
+	}
+	return book, true
+}
+
+func (c *Cache) lookup(values map[key]value, exchange string, symbols []string) (map[string]float64, []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make(map[string]float64, len(symbols))
+	var missing []string
+	now := time.Now()
+	for _, symbol := range symbols {
+		v, ok := values[key{exchange, symbol}]
+		if !ok || now.Sub(v.at) > c.maxAge {
+			missing = append(missing, symbol)
+			continue
+		}
+		out[symbol] = v.v
+	}
+	return out, missing
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/marketdata"
+)
+
+// instrumented wraps an Exchange, timing every call and recording the
+// market and account values it returns.
+type instrumented struct {
+	venue string
+	next  exchange.Exchange
+}
+
+// Instrument wraps exc so its calls are reported under the given venue name.
+func Instrument(venue string, exc exchange.Exchange) exchange.Exchange {
+	return &instrumented{venue: venue, next: exc}
+}
+
+var _ exchange.Exchange = (*instrumented)(nil)
+
+func (i *instrumented) observe(method string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	APILatency.WithLabelValues(i.venue, method, result).Observe(time.Since(start).Seconds())
+}
+
+func (i *instrumented) GetFundingRate(symbol string) (float64, error) {
+	start := time.Now()
+	rate, err := i.next.GetFundingRate(symbol)
+	i.observe("GetFundingRate", start, err)
+	if err == nil {
+		FundingRate.WithLabelValues(i.venue, symbol).Set(rate)
+	}
+	return rate, err
+}
+
+func (i *instrumented) GetPrice(symbol string) (float64, error) {
+	start := time.Now()
+	price, err := i.next.GetPrice(symbol)
+	i.observe("GetPrice", start, err)
+	if err == nil {
+		Price.WithLabelValues(i.venue, symbol).Set(price)
+	}
+	return price, err
+}
+
+func (i *instrumented) GetFundingRates(symbols []string) (map[string]float64, error) {
+	start := time.Now()
+	rates, err := i.next.GetFundingRates(symbols)
+	i.observe("GetFundingRates", start, err)
+	for symbol, rate := range rates {
+		FundingRate.WithLabelValues(i.venue, symbol).Set(rate)
+	}
+	return rates, err
+}
+
+func (i *instrumented) GetPrices(symbols []string) (map[string]float64, error) {
+	start := time.Now()
+	prices, err := i.next.GetPrices(symbols)
+	i.observe("GetPrices", start, err)
+	for symbol, price := range prices {
+		Price.WithLabelValues(i.venue, symbol).Set(price)
+	}
+	return prices, err
+}
+
+func (i *instrumented) GetOrderBook(symbol string) (*marketdata.Book, error) {
+	start := time.Now()
+	book, err := i.next.GetOrderBook(symbol)
+	i.observe("GetOrderBook", start, err)
+	return book, err
+}
+
+func (i *instrumented) GetBalance(asset string) (float64, error) {
+	start := time.Now()
+	balance, err := i.next.GetBalance(asset)
+	i.observe("GetBalance", start, err)
+	if err == nil {
+		Balance.WithLabelValues(i.venue, asset).Set(balance)
+	}
+	return balance, err
+}
+
+func (i *instrumented) GetPosition(symbol string) (*exchange.Position, error) {
+	start := time.Now()
+	pos, err := i.next.GetPosition(symbol)
+	i.observe("GetPosition", start, err)
+	if err == nil && pos != nil {
+		PositionSize.WithLabelValues(i.venue, symbol).Set(pos.Size)
+	}
+	return pos, err
+}
+
+func (i *instrumented) GetPositions() ([]*exchange.Position, error) {
+	start := time.Now()
+	positions, err := i.next.GetPositions()
+	i.observe("GetPositions", start, err)
+	if err == nil {
+		// Drop series for positions that have been closed
+		PositionSize.DeletePartialMatch(map[string]string{"venue": i.venue})
+		for _, pos := range positions {
+			PositionSize.WithLabelValues(i.venue, pos.Symbol).Set(pos.Size)
+		}
+	}
+	return positions, err
+}
+
+func (i *instrumented) GetOpenOrders() ([]*exchange.Order, error) {
+	start := time.Now()
+	orders, err := i.next.GetOpenOrders()
+	i.observe("GetOpenOrders", start, err)
+	return orders, err
+}
+
+func (i *instrumented) GetFundingPayments(since time.Time) ([]*exchange.FundingPayment, error) {
+	start := time.Now()
+	payments, err := i.next.GetFundingPayments(since)
+	i.observe("GetFundingPayments", start, err)
+	return payments, err
+}
+
+func (i *instrumented) PlaceOrder(req *exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	start := time.Now()
+	res, err := i.next.PlaceOrder(req)
+	i.observe("PlaceOrder", start, err)
+	if err != nil {
+		OrdersFailed.WithLabelValues(i.venue).Inc()
+	} else {
+		OrdersPlaced.WithLabelValues(i.venue).Inc()
+	}
+	return res, err
+}
+
+func (i *instrumented) CancelOrder(symbol, orderID string) error {
+	start := time.Now()
+	err := i.next.CancelOrder(symbol, orderID)
+	i.observe("CancelOrder", start, err)
+	return err
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"arbitrage-bot/internal/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "arb"
+
+var (
+	FundingRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "funding_rate",
+		Help:      "Latest funding rate per venue and pair.",
+	}, []string{"venue", "symbol"})
+
+	Price = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "price",
+		Help:      "Latest price per venue and pair.",
+	}, []string{"venue", "symbol"})
+
+	BestSpread = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "best_funding_spread",
+		Help:      "Largest funding rate difference between venues per pair.",
+	}, []string{"symbol"})
+
+	PositionSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "position_size",
+		Help:      "Signed open position size per venue and pair.",
+	}, []string{"venue", "symbol"})
+
+	Balance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "balance",
+		Help:      "Account balance per venue and asset.",
+	}, []string{"venue", "asset"})
+
+	VenueReady = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "venue_ready",
+		Help:      "1 if the venue passed its readiness check and is used by strategies.",
+	}, []string{"venue"})
+
+	VenueBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "venue_breaker_state",
+		Help:      "Circuit breaker state per venue: 0 closed, 1 half-open, 2 open.",
+	}, []string{"venue"})
+
+	OrdersPlaced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_placed_total",
+		Help:      "Orders accepted per venue.",
+	}, []string{"venue"})
+
+	OrdersFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_failed_total",
+		Help:      "Orders rejected or failed per venue.",
+	}, []string{"venue"})
+
+	EventsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_published_total",
+		Help:      "Events published on the internal bus per kind.",
+	}, []string{"kind"})
+
+	EventsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_dropped_total",
+		Help:      "Events dropped because a subscriber's buffer was full, per subscriber and kind.",
+	}, []string{"subscriber", "kind"})
+
+	APILatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "api_latency_seconds",
+		Help:      "Latency of exchange adapter calls.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"venue", "method", "result"})
+)
+
+// Handler serves the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve exposes /metrics on port, without authentication so Prometheus can
+// scrape it, until ctx is cancelled.
+func Serve(ctx context.Context, port int) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("metrics listening", "addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("metrics server stopped", logger.Err(err))
+	}
+}
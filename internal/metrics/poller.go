@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+)
+
+var log = logger.For("metrics")
+
+// PollAccounts refreshes balances and positions on every exchange at the
+// given interval so their gauges stay current. exchanges should already be
+// wrapped with Instrument.
+func PollAccounts(ctx context.Context, exchanges map[string]exchange.Exchange, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for name, exc := range exchanges {
+			if _, err := exc.GetBalance("USDC"); err != nil {
+				log.Debug("failed to get balance", logger.KeyVenue, name, logger.Err(err))
+			}
+			if _, err := exc.GetPositions(); err != nil {
+				log.Debug("failed to get positions", logger.KeyVenue, name, logger.Err(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"arbitrage-bot/internal/config"
+)
+
+// NewRouterFromConfig builds a router with the configured sinks.
+func NewRouterFromConfig(cfg config.NotificationsConfig) (*Router, error) {
+	r := NewRouter(time.Duration(cfg.DedupWindowMs)*time.Millisecond, cfg.RateLimitPerMinute)
+
+	for i, sc := range cfg.Sinks {
+		sev, err := ParseSeverity(sc.MinSeverity)
+		if err != nil {
+			return nil, fmt.Errorf("sink %d: %w", i, err)
+		}
+
+		var n Notifier
+		switch sc.Type {
+		case "webhook":
+			if sc.URL == "" {
+				return nil, fmt.Errorf("sink %d: webhook requires url", i)
+			}
+			n = NewWebhookNotifier(sc.URL)
+		case "telegram":
+			if sc.BotToken == "" || sc.ChatID == "" {
+				return nil, fmt.Errorf("sink %d: telegram requires bot_token and chat_id", i)
+			}
+			n = NewTelegramNotifier(sc.URL, string(sc.BotToken), sc.ChatID)
+		case "slack":
+			if sc.URL == "" {
+				return nil, fmt.Errorf("sink %d: slack requires url", i)
+			}
+			n = NewSlackNotifier(sc.URL)
+		case "file":
+			if sc.Path == "" {
+				return nil, fmt.Errorf("sink %d: file requires path", i)
+			}
+			n = NewFileNotifier(sc.Path)
+		default:
+			return nil, fmt.Errorf("sink %d: unknown type %q", i, sc.Type)
+		}
+		r.Add(n, sev)
+	}
+	return r, nil
+}
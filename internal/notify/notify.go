@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity orders events for routing.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity parses info, warning or critical. Empty means info.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "", "info":
+		return SeverityInfo, nil
+	case "warn", "warning":
+		return SeverityWarning, nil
+	case "critical", "error":
+		return SeverityCritical, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q", s)
+}
+
+// Event is something an operator should hear about.
+type Event struct {
+	Severity Severity       `json:"severity"`
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Fields   map[string]any `json:"fields,omitempty"`
+	Time     time.Time      `json:"time"`
+
+	// DedupKey identifies repeats of the same alert. Defaults to Title,
+	// Message and Fields.
+	DedupKey string `json:"-"`
+}
+
+// Text renders the event as plain text for chat sinks.
+func (e Event) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", strings.ToUpper(e.Severity.String()), e.Title)
+	if e.Message != "" {
+		b.WriteString("\n")
+		b.WriteString(e.Message)
+	}
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s: %v", k, e.Fields[k])
+	}
+	return b.String()
+}
+
+// Notifier delivers events to one destination.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, e Event) error
+}
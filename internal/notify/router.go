@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/logger"
+)
+
+var log = logger.For("notify")
+
+const (
+	queueSize   = 256
+	sendTimeout = 15 * time.Second
+)
+
+type route struct {
+	notifier    Notifier
+	minSeverity Severity
+
+	// sliding window of recent sends, for the per-sink rate limit
+	sent []time.Time
+}
+
+// Router fans events out to sinks by severity, dropping repeats of the same
+// alert inside the dedup window and capping each sink's send rate. Sending
+// happens on a background goroutine so callers never block on the network.
+type Router struct {
+	routes      []*route
+	dedupWindow time.Duration
+	ratePerMin  int
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+
+	queue chan Event
+}
+
+// NewRouter creates a router. A zero dedupWindow disables dedup and a zero
+// ratePerMin disables rate limiting.
+func NewRouter(dedupWindow time.Duration, ratePerMin int) *Router {
+	return &Router{
+		dedupWindow: dedupWindow,
+		ratePerMin:  ratePerMin,
+		lastSeen:    make(map[string]time.Time),
+		queue:       make(chan Event, queueSize),
+	}
+}
+
+// Add registers a sink that receives events at or above minSeverity.
+// Call before Start.
+func (r *Router) Add(n Notifier, minSeverity Severity) {
+	r.routes = append(r.routes, &route{notifier: n, minSeverity: minSeverity})
+}
+
+// Start delivers queued events until ctx is cancelled, then drains what is
+// left in the queue.
+func (r *Router) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-r.queue:
+					r.deliver(e)
+				default:
+					return
+				}
+			}
+		case e := <-r.queue:
+			r.deliver(e)
+		}
+	}
+}
+
+// Notify queues an event. It is safe on a nil Router and never blocks; if
+// the queue is full the event is dropped and logged.
+func (r *Router) Notify(e Event) {
+	if r == nil || len(r.routes) == 0 {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if r.duplicate(e) {
+		log.Debug("duplicate alert suppressed", "title", e.Title)
+		return
+	}
+
+	select {
+	case r.queue <- e:
+	default:
+		log.Warn("notification queue full, dropping alert", "title", e.Title)
+	}
+}
+
+// Info, Warn and Critical are shorthands for Notify.
+func (r *Router) Info(title, message string, fields map[string]any) {
+	r.Notify(Event{Severity: SeverityInfo, Title: title, Message: message, Fields: fields})
+}
+
+func (r *Router) Warn(title, message string, fields map[string]any) {
+	r.Notify(Event{Severity: SeverityWarning, Title: title, Message: message, Fields: fields})
+}
+
+func (r *Router) Critical(title, message string, fields map[string]any) {
+	r.Notify(Event{Severity: SeverityCritical, Title: title, Message: message, Fields: fields})
+}
+
+func (r *Router) duplicate(e Event) bool {
+	if r.dedupWindow <= 0 {
+		return false
+	}
+	key := e.DedupKey
+	if key == "" {
+		key = defaultDedupKey(e)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, t := range r.lastSeen {
+		if e.Time.Sub(t) >= r.dedupWindow {
+			delete(r.lastSeen, k)
+		}
+	}
+	if _, ok := r.lastSeen[key]; ok {
+		return true
+	}
+	r.lastSeen[key] = e.Time
+	return false
+}
+
+// defaultDedupKey is the title, message and fields, so the same alert about
+// a different pair or venue is not a repeat.
+func defaultDedupKey(e Event) string {
+	var b strings.Builder
+	b.WriteString(e.Title)
+	b.WriteByte(0)
+	b.WriteString(e.Message)
+	for _, k := range slices.Sorted(maps.Keys(e.Fields)) {
+		fmt.Fprintf(&b, "\x00%s=%v", k, e.Fields[k])
+	}
+	return b.String()
+}
+
+func (r *Router) deliver(e Event) {
+	for _, rt := range r.routes {
+		if e.Severity < rt.minSeverity {
+			continue
+		}
+		if !rt.allow(r.ratePerMin, time.Now()) {
+			log.Warn("notification rate limit hit, dropping alert",
+				"sink", rt.notifier.Name(), "title", e.Title)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := rt.notifier.Notify(ctx, e); err != nil {
+			log.Error("failed to send notification", "sink", rt.notifier.Name(),
+				"title", e.Title, logger.Err(err))
+		}
+		cancel()
+	}
+}
+
+// allow is only called from the delivery goroutine.
+func (rt *route) allow(perMin int, now time.Time) bool {
+	if perMin <= 0 {
+		return true
+	}
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(rt.sent) && rt.sent[i].Before(cutoff) {
+		i++
+	}
+	rt.sent = rt.sent[i:]
+	if len(rt.sent) >= perMin {
+		return false
+	}
+	rt.sent = append(rt.sent, now)
+	return true
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON posts body to target. Errors name the endpoint instead of the
+// URL, which may carry a token.
+func postJSON(ctx context.Context, endpoint, target string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid %s URL", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("POST %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s failed with status %d: %s", endpoint, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the event as JSON to a URL.
+type WebhookNotifier struct {
+	url string
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Notify(ctx context.Context, e Event) error {
+	return postJSON(ctx, "webhook", n.url, e)
+}
+
+// TelegramNotifier sends the event through a Telegram bot.
+type TelegramNotifier struct {
+	baseURL  string
+	botToken string
+	chatID   string
+}
+
+const defaultTelegramURL = "https://api.telegram.org"
+
+// NewTelegramNotifier creates a Telegram sink. baseURL may be empty to use
+// the public Bot API.
+func NewTelegramNotifier(baseURL, botToken, chatID string) *TelegramNotifier {
+	if baseURL == "" {
+		baseURL = defaultTelegramURL
+	}
+	return &TelegramNotifier{baseURL: baseURL, botToken: botToken, chatID: chatID}
+}
+
+func (n *TelegramNotifier) Name() string { return "telegram" }
+
+func (n *TelegramNotifier) Notify(ctx context.Context, e Event) error {
+	target := fmt.Sprintf("%s/bot%s/sendMessage", n.baseURL, n.botToken)
+	return postJSON(ctx, "telegram sendMessage", target, map[string]string{
+		"chat_id": n.chatID,
+		"text":    e.Text(),
+	})
+}
+
+// SlackNotifier posts to a Slack incoming webhook.
+type SlackNotifier struct {
+	webhookURL string
+}
+
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL}
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+func (n *SlackNotifier) Notify(ctx context.Context, e Event) error {
+	return postJSON(ctx, "slack webhook", n.webhookURL, map[string]string{"text": e.Text()})
+}
+
+// FileNotifier appends events as JSON lines to a local file.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Name() string { return "file" }
+
+func (n *FileNotifier) Notify(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(n.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
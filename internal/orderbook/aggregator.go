@@ -0,0 +1,362 @@
+package orderbook
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/events"
+	"arbitrage-bot/internal/marketdata"
+)
+
+// Book updates buffered before the bus drops them; the next update of the
+// same book replaces a dropped one anyway
+const updateBuffer = 1024
+
+var (
+	ErrNoBook            = errors.New("no order book")
+	ErrStale             = errors.New("order book is stale")
+	ErrInsufficientDepth = errors.New("not enough depth")
+)
+
+type key struct {
+	exchange string
+	symbol   string
+}
+
+// Aggregator holds the latest order book of every exchange for each pair,
+// in coins and USD per coin, and answers cross-venue questions about them.
+// Books older than the max age are still reported, marked stale, but left
+// out of consolidated depth and spreads.
+type Aggregator struct {
+	maxAge time.Duration
+	units  map[key]float64 // contract size, if not 1
+
+	mu    sync.RWMutex
+	books map[string]map[string]marketdata.Book // by pair, then exchange
+}
+
+// Staleness is how old a venue's book is.
+type Staleness struct {
+	At    time.Time `json:"at"`
+	AgeMs int64     `json:"age_ms"`
+	Stale bool      `json:"stale"` // older than the max age
+}
+
+// VenueBook is an exchange's normalized book.
+type VenueBook struct {
+	Exchange string          `json:"exchange"`
+	Book     marketdata.Book `json:"book"`
+	Staleness
+}
+
+// Execution is what taking size from one exchange's book would cost.
+type Execution struct {
+	Exchange   string  `json:"exchange"`
+	Side       string  `json:"side"` // "buy" takes asks, "sell" takes bids
+	Size       float64 `json:"size"`
+	Filled     float64 `json:"filled"` // less than size if the book is too thin
+	AvgPrice   float64 `json:"avg_price"`
+	WorstPrice float64 `json:"worst_price"`
+	Staleness
+}
+
+// Complete reports whether the book had enough depth for the whole size.
+func (e Execution) Complete() bool {
+	return e.Filled >= e.Size
+}
+
+// DepthLevel is a price level of the consolidated book, with the size each
+// exchange shows at it.
+type DepthLevel struct {
+	Price  float64            `json:"price"`
+	Size   float64            `json:"size"`
+	Venues map[string]float64 `json:"venues"`
+}
+
+// Depth is the consolidated book of a pair over the exchanges with a fresh
+// book.
+type Depth struct {
+	Bids   []DepthLevel         `json:"bids"`
+	Asks   []DepthLevel         `json:"asks"`
+	Venues map[string]Staleness `json:"venues"` // every exchange with a book, stale ones included
+}
+
+// Spread is the effective spread of buying size on one exchange and selling
+// it on another at their volume-weighted prices, relative to the buy price.
+// Positive means the sale pays more than the purchase costs.
+type Spread struct {
+	BuyExchange  string  `json:"buy_exchange"`
+	SellExchange string  `json:"sell_exchange"`
+	Size         float64 `json:"size"`
+	BuyPrice     float64 `json:"buy_price"`
+	SellPrice    float64 `json:"sell_price"`
+	Spread       float64 `json:"spread"`
+}
+
+// NewAggregator creates an aggregator whose books are fresh for maxAge,
+// converting the books listed in units.
+func NewAggregator(maxAge time.Duration, units []config.BookUnit) *Aggregator {
+	a := &Aggregator{
+		maxAge: maxAge,
+		units:  make(map[key]float64, len(units)),
+		books:  make(map[string]map[string]marketdata.Book),
+	}
+	for _, u := range units {
+		a.units[key{u.Exchange, u.Symbol}] = u.ContractSize
+	}
+	return a
+}
+
+// Run keeps the books up to date from the book updates on bus until ctx is
+// cancelled.
+func (a *Aggregator) Run(ctx context.Context, bus *events.Bus) {
+	sub := bus.Subscribe("orderbook", updateBuffer, events.KindBook)
+	defer bus.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-sub.Events():
+			if u, ok := e.(events.BookUpdate); ok {
+				a.Update(u.Exchange, u.Symbol, u.Book)
+			}
+		}
+	}
+}
+
+// Update replaces the book of symbol on exchange, as the venue quotes it.
+func (a *Aggregator) Update(exchange, symbol string, book marketdata.Book) {
+	if size, ok := a.units[key{exchange, symbol}]; ok {
+		book = convert(book, size)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.books[symbol] == nil {
+		a.books[symbol] = make(map[string]marketdata.Book)
+	}
+	a.books[symbol][exchange] = book
+}
+
+// convert turns levels quoted per lot of contractSize coins into levels per
+// coin.
+func convert(book marketdata.Book, contractSize float64) marketdata.Book {
+	scale := func(levels []marketdata.Level) []marketdata.Level {
+		out := make([]marketdata.Level, len(levels))
+		for i, l := range levels {
+			out[i] = marketdata.Level{Price: l.Price / contractSize, Size: l.Size * contractSize}
+		}
+		return out
+	}
+	return marketdata.Book{Bids: scale(book.Bids), Asks: scale(book.Asks), At: book.At}
+}
+
+func (a *Aggregator) staleness(at, now time.Time) Staleness {
+	age := now.Sub(at)
+	return Staleness{At: at, AgeMs: age.Milliseconds(), Stale: age > a.maxAge}
+}
+
+// Books returns the book of symbol on every exchange that has one, by
+// exchange name.
+func (a *Aggregator) Books(symbol string) []VenueBook {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	now := time.Now()
+	out := make([]VenueBook, 0, len(a.books[symbol]))
+	for exchange, book := range a.books[symbol] {
+		out = append(out, VenueBook{Exchange: exchange, Book: book, Staleness: a.staleness(book.At, now)})
+	}
+	slices.SortFunc(out, func(x, y VenueBook) int { return cmp.Compare(x.Exchange, y.Exchange) })
+	return out
+}
+
+// Executions returns the cost of buying or selling size of symbol on each
+// exchange, best first: fresh books that can fill the whole size, then by
+// average price.
+func (a *Aggregator) Executions(symbol, side string, size float64) []Execution {
+	books := a.Books(symbol)
+	out := make([]Execution, 0, len(books))
+	for _, b := range books {
+		out = append(out, execute(b, side, size))
+	}
+
+	slices.SortStableFunc(out, func(x, y Execution) int {
+		if r := cmp.Compare(rank(x), rank(y)); r != 0 {
+			return r
+		}
+		if side == "buy" {
+			return cmp.Compare(x.AvgPrice, y.AvgPrice)
+		}
+		return cmp.Compare(y.AvgPrice, x.AvgPrice)
+	})
+	return out
+}
+
+// rank orders executions by how usable they are, lowest first.
+func rank(e Execution) int {
+	switch {
+	case !e.Stale && e.Complete():
+		return 0
+	case !e.Stale:
+		return 1
+	}
+	return 2
+}
+
+// execute walks the side of the book a taker of side would hit, best level
+// first, until size is filled.
+func execute(b VenueBook, side string, size float64) Execution {
+	e := Execution{Exchange: b.Exchange, Side: side, Size: size, Staleness: b.Staleness}
+	levels := b.Book.Asks
+	if side == "sell" {
+		levels = b.Book.Bids
+	}
+
+	var notional float64
+	remaining := size
+	for _, l := range levels {
+		if remaining <= 0 {
+			break
+		}
+		take := min(l.Size, remaining)
+		remaining -= take // exactly 0 once filled, so Complete holds
+		notional += take * l.Price
+		e.WorstPrice = l.Price
+	}
+	e.Filled = size - max(remaining, 0)
+	if e.Filled > 0 {
+		e.AvgPrice = notional / e.Filled
+	}
+	return e
+}
+
+// Depth merges the fresh books of symbol, up to levels price levels per side
+// (0 for all).
+func (a *Aggregator) Depth(symbol string, levels int) Depth {
+	d := Depth{Venues: make(map[string]Staleness)}
+	bids := make(map[float64]*DepthLevel)
+	asks := make(map[float64]*DepthLevel)
+
+	for _, b := range a.Books(symbol) {
+		d.Venues[b.Exchange] = b.Staleness
+		if b.Stale {
+			continue
+		}
+		for _, side := range []struct {
+			levels []marketdata.Level
+			merged map[float64]*DepthLevel
+		}{{b.Book.Bids, bids}, {b.Book.Asks, asks}} {
+			for _, l := range side.levels {
+				m := side.merged[l.Price]
+				if m == nil {
+					m = &DepthLevel{Price: l.Price, Venues: make(map[string]float64)}
+					side.merged[l.Price] = m
+				}
+				m.Size += l.Size
+				m.Venues[b.Exchange] += l.Size
+			}
+		}
+	}
+
+	d.Bids = sortedLevels(bids, true, levels)
+	d.Asks = sortedLevels(asks, false, levels)
+	return d
+}
+
+// sortedLevels returns up to n levels (0 for all), best first.
+func sortedLevels(merged map[float64]*DepthLevel, descending bool, n int) []DepthLevel {
+	out := make([]DepthLevel, 0, len(merged))
+	for _, l := range merged {
+		out = append(out, *l)
+	}
+	slices.SortFunc(out, func(x, y DepthLevel) int {
+		if descending {
+			return cmp.Compare(y.Price, x.Price)
+		}
+		return cmp.Compare(x.Price, y.Price)
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Spread returns the effective spread of buying size of symbol on
+// buyExchange and selling it on sellExchange. Both books must be fresh and
+// deep enough.
+func (a *Aggregator) Spread(symbol, buyExchange, sellExchange string, size float64) (Spread, error) {
+	books := a.Books(symbol)
+	buy, err := fill(books, buyExchange, "buy", size)
+	if err != nil {
+		return Spread{}, err
+	}
+	sell, err := fill(books, sellExchange, "sell", size)
+	if err != nil {
+		return Spread{}, err
+	}
+	return newSpread(buy, sell), nil
+}
+
+// Spreads returns the effective spread of every pair of exchanges with
+// fresh books deep enough for size, best first.
+func (a *Aggregator) Spreads(symbol string, size float64) []Spread {
+	var buys, sells []Execution
+	for _, b := range a.Books(symbol) {
+		if b.Stale {
+			continue
+		}
+		if e := execute(b, "buy", size); e.Complete() {
+			buys = append(buys, e)
+		}
+		if e := execute(b, "sell", size); e.Complete() {
+			sells = append(sells, e)
+		}
+	}
+
+	var out []Spread
+	for _, buy := range buys {
+		for _, sell := range sells {
+			if buy.Exchange != sell.Exchange {
+				out = append(out, newSpread(buy, sell))
+			}
+		}
+	}
+	slices.SortFunc(out, func(x, y Spread) int { return cmp.Compare(y.Spread, x.Spread) })
+	return out
+}
+
+// fill executes size on exchange's book, which must be fresh and deep
+// enough.
+func fill(books []VenueBook, exchange, side string, size float64) (Execution, error) {
+	i := slices.IndexFunc(books, func(b VenueBook) bool { return b.Exchange == exchange })
+	if i < 0 {
+		return Execution{}, fmt.Errorf("%w for %s", ErrNoBook, exchange)
+	}
+	if books[i].Stale {
+		return Execution{}, fmt.Errorf("%w: %s is %dms old", ErrStale, exchange, books[i].AgeMs)
+	}
+	e := execute(books[i], side, size)
+	if !e.Complete() {
+		return Execution{}, fmt.Errorf("%w: %s can %s %g of %g", ErrInsufficientDepth, exchange, side, e.Filled, size)
+	}
+	return e, nil
+}
+
+func newSpread(buy, sell Execution) Spread {
+	return Spread{
+		BuyExchange:  buy.Exchange,
+		SellExchange: sell.Exchange,
+		Size:         buy.Size,
+		BuyPrice:     buy.AvgPrice,
+		SellPrice:    sell.AvgPrice,
+		Spread:       (sell.AvgPrice - buy.AvgPrice) / buy.AvgPrice,
+	}
+}
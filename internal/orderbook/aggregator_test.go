@@ -0,0 +1,167 @@
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"arbitrage-bot/internal/marketdata"
+)
+
+func levels(pairs ...float64) []marketdata.Level {
+	out := make([]marketdata.Level, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		out = append(out, marketdata.Level{Price: pairs[i], Size: pairs[i+1]})
+	}
+	return out
+}
+
+func TestExecute(t *testing.T) {
+	book := VenueBook{
+		Exchange: "a",
+		Book: marketdata.Book{
+			Bids: levels(99, 1, 98, 2),
+			Asks: levels(100, 1, 101, 2),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		book     VenueBook
+		side     string
+		size     float64
+		filled   float64
+		avg      float64
+		worst    float64
+		complete bool
+	}{
+		{"buy within top level", book, "buy", 0.5, 0.5, 100, 100, true},
+		{"buy across levels", book, "buy", 2, 2, 100.5, 101, true},
+		{"buy whole book", book, "buy", 3, 3, (100 + 202) / 3.0, 101, true},
+		{"buy more than the book", book, "buy", 5, 3, (100 + 202) / 3.0, 101, false},
+		{"sell across levels", book, "sell", 2, 2, 98.5, 98, true},
+		{"empty book", VenueBook{Exchange: "a"}, "sell", 1, 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := execute(tt.book, tt.side, tt.size)
+			if e.Size != tt.size || e.Filled != tt.filled {
+				t.Errorf("size %g filled %g, want %g filled %g", e.Size, e.Filled, tt.size, tt.filled)
+			}
+			if e.AvgPrice != tt.avg || e.WorstPrice != tt.worst {
+				t.Errorf("avg %g worst %g, want avg %g worst %g", e.AvgPrice, e.WorstPrice, tt.avg, tt.worst)
+			}
+			if e.Complete() != tt.complete {
+				t.Errorf("complete = %v, want %v", e.Complete(), tt.complete)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name         string
+		book         marketdata.Book
+		contractSize float64
+		want         marketdata.Book
+	}{
+		{
+			name:         "lots of 1000 coins",
+			book:         marketdata.Book{Bids: levels(5, 2), Asks: levels(6, 3), At: at},
+			contractSize: 1000,
+			want:         marketdata.Book{Bids: levels(0.005, 2000), Asks: levels(0.006, 3000), At: at},
+		},
+		{
+			name:         "fractional contracts",
+			book:         marketdata.Book{Bids: levels(10, 4, 9, 8), At: at},
+			contractSize: 0.5,
+			want:         marketdata.Book{Bids: levels(20, 2, 18, 4), Asks: levels(), At: at},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convert(tt.book, tt.contractSize)
+			if !got.At.Equal(tt.want.At) {
+				t.Errorf("at = %v, want %v", got.At, tt.want.At)
+			}
+			for _, side := range []struct {
+				name      string
+				got, want []marketdata.Level
+			}{{"bids", got.Bids, tt.want.Bids}, {"asks", got.Asks, tt.want.Asks}} {
+				if len(side.got) != len(side.want) {
+					t.Fatalf("%s = %v, want %v", side.name, side.got, side.want)
+				}
+				for i := range side.got {
+					if side.got[i].Price != side.want[i].Price || side.got[i].Size != side.want[i].Size {
+						t.Errorf("%s[%d] = %v, want %v", side.name, i, side.got[i], side.want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSpreads(t *testing.T) {
+	now := time.Now()
+	fresh := map[string]marketdata.Book{
+		"a": {Bids: levels(99, 10), Asks: levels(100, 10), At: now},
+		"b": {Bids: levels(101, 10), Asks: levels(102, 10), At: now},
+		"c": {Bids: levels(100.5, 10), Asks: levels(101, 10), At: now},
+	}
+	// Best first: the largest (sell - buy) / buy
+	ordered := [][2]string{{"a", "b"}, {"a", "c"}, {"c", "b"}, {"b", "c"}, {"c", "a"}, {"b", "a"}}
+
+	tests := []struct {
+		name  string
+		extra map[string]marketdata.Book
+		want  [][2]string // buy, sell
+	}{
+		{"fresh books", nil, ordered},
+		{
+			name:  "stale book left out",
+			extra: map[string]marketdata.Book{"d": {Bids: levels(200, 10), Asks: levels(1, 10), At: now.Add(-2 * time.Minute)}},
+			want:  ordered,
+		},
+		{
+			name:  "thin book left out",
+			extra: map[string]marketdata.Book{"e": {Bids: levels(300, 0.5), Asks: levels(1, 0.5), At: now}},
+			want:  ordered,
+		},
+		{
+			name:  "one side deep enough",
+			extra: map[string]marketdata.Book{"f": {Bids: levels(103, 10), Asks: levels(1, 0.5), At: now}},
+			want: [][2]string{
+				{"a", "f"}, {"c", "f"}, {"a", "b"}, {"b", "f"},
+				{"a", "c"}, {"c", "b"}, {"b", "c"}, {"c", "a"}, {"b", "a"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAggregator(time.Minute, nil)
+			for exchange, book := range fresh {
+				a.Update(exchange, "ETH-USD", book)
+			}
+			for exchange, book := range tt.extra {
+				a.Update(exchange, "ETH-USD", book)
+			}
+
+			got := a.Spreads("ETH-USD", 1)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d spreads, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, s := range got {
+				if s.BuyExchange != tt.want[i][0] || s.SellExchange != tt.want[i][1] {
+					t.Errorf("spread %d is %s->%s, want %s->%s", i, s.BuyExchange, s.SellExchange, tt.want[i][0], tt.want[i][1])
+				}
+				if want := (s.SellPrice - s.BuyPrice) / s.BuyPrice; s.Spread != want {
+					t.Errorf("spread %d = %g, want %g", i, s.Spread, want)
+				}
+				if i > 0 && s.Spread > got[i-1].Spread {
+					t.Errorf("spread %d (%g) is better than spread %d (%g)", i, s.Spread, i-1, got[i-1].Spread)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Arb pair statuses
+const (
+	StatusOpen     = "open"     // both legs placed
+	StatusUnhedged = "unhedged" // only one leg is live, needs attention
+	StatusClosed   = "closed"
+)
+
+// ArbPair is one funding arbitrage trade: a long leg on one exchange
+// hedged by a short leg on another.
+type ArbPair struct {
+	ID            string    `json:"id"`
+	Symbol        string    `json:"symbol"`
+	LongExchange  string    `json:"long_exchange"`
+	ShortExchange string    `json:"short_exchange"`
+	Size          float64   `json:"size"`
+	LongOrderID   string    `json:"long_order_id,omitempty"`
+	ShortOrderID  string    `json:"short_order_id,omitempty"`
+	EntryDiff     float64   `json:"entry_diff"`
+	Status        string    `json:"status"`
+	OpenedAt      time.Time `json:"opened_at"`
+	ClosedAt      time.Time `json:"closed_at,omitzero"`
+
+	// Reduce-only orders that unwind the legs
+	LongCloseOrderID  string `json:"long_close_order_id,omitempty"`
+	ShortCloseOrderID string `json:"short_close_order_id,omitempty"`
+
+	// Size held on each leg: opening fills less closing fills
+	LongFilled  float64 `json:"long_filled"`
+	ShortFilled float64 `json:"short_filled"`
+}
+
+// Active reports whether the pair still holds (or may hold) exposure.
+func (p *ArbPair) Active() bool {
+	return p.Status == StatusOpen || p.Status == StatusUnhedged
+}
+
+// Orphan is a position or resting order found on an exchange that does not
+// belong to any known arb pair.
+type Orphan struct {
+	Exchange   string    `json:"exchange"`
+	Kind       string    `json:"kind"` // "position" or "order"
+	Symbol     string    `json:"symbol"`
+	OrderID    string    `json:"order_id,omitempty"`
+	Size       float64   `json:"size"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// State is everything a strategy persists between restarts.
+type State struct {
+	ArbPairs  []*ArbPair `json:"arb_pairs"`
+	Orphans   []*Orphan  `json:"orphans"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Store persists State as a JSON file.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the persisted state. A missing file yields an empty state.
+func (s *Store) Load() (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", s.path, err)
+	}
+	return &st, nil
+}
+
+// Save writes the state atomically (write to a temp file, then rename).
+func (s *Store) Save(st *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -2,63 +2,272 @@ package strategy
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/events"
 	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/ledger"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/metrics"
+	"arbitrage-bot/internal/notify"
+	"arbitrage-bot/internal/state"
 )
 
+// Number of recent opportunities kept for the status API
+const maxOpportunities = 100
+
+// Consecutive funding rate failures before a venue is reported as down
+const venueDownAfter = 5
+
+// Leg size used when max_notional is not set
+const testSize = 0.01
+
+// Pushed events buffered between ticks before the bus drops them
+const eventBuffer = 256
+
 type FundingArbStrategy struct {
-	cfg       config.FundingArbConfig
-	exchanges map[string]exchange.Exchange
-	stopCh    chan struct{}
+	venues   *exchange.Registry
+	store    *state.Store
+	ledger   *ledger.Ledger
+	notifier *notify.Router
+	bus      *events.Bus // may be nil
+	log      *slog.Logger
+	stopCh   chan struct{}
+
+	// consecutive funding rate failures by exchange, only touched by Start
+	failures map[string]int
+
+	running       atomic.Bool
+	paused        atomic.Bool
+	executeTrades atomic.Bool
+
+	mu            sync.Mutex
+	cfg           config.FundingArbConfig // replaced on hot reload
+	pairs         []*state.ArbPair        // all known arb pairs, including closed ones
+	orphans       []*state.Orphan
+	quotes        map[string]map[string]Quote // latest funding rate by pair, then exchange
+	ratesAt       time.Time                   // last tick
+	fetching      map[string]bool             // venues with a funding rate request running
+	closing       map[string]bool             // arb pairs with close orders in flight
+	opportunities []Opportunity               // most recent last
 }
 
-func NewFundingArbStrategy(cfg config.FundingArbConfig, exchanges map[string]exchange.Exchange) *FundingArbStrategy {
-	return &FundingArbStrategy{
-		cfg:       cfg,
-		exchanges: exchanges,
-		stopCh:    make(chan struct{}),
+// Opportunity is a funding spread at or above the threshold.
+type Opportunity struct {
+	Symbol        string    `json:"symbol"`
+	LongExchange  string    `json:"long_exchange"`
+	ShortExchange string    `json:"short_exchange"`
+	LongRate      float64   `json:"long_rate"`
+	ShortRate     float64   `json:"short_rate"`
+	Diff          float64   `json:"diff"`
+	Executed      bool      `json:"executed"`
+	DetectedAt    time.Time `json:"detected_at"`
+}
+
+// FundingArbDetails is the strategy-specific part of Status.
+type FundingArbDetails struct {
+	Pairs    []string                     `json:"pairs"`
+	Params   map[string]config.PairParams `json:"params"` // effective settings per pair
+	ArbPairs []*state.ArbPair             `json:"arb_pairs"`
+	Orphans  []*state.Orphan              `json:"orphans"`
+}
+
+func NewFundingArbStrategy(cfg config.FundingArbConfig, venues *exchange.Registry, store *state.Store, ldg *ledger.Ledger, notifier *notify.Router, bus *events.Bus) *FundingArbStrategy {
+	s := &FundingArbStrategy{
+		cfg:      cfg,
+		venues:   venues,
+		store:    store,
+		ledger:   ldg,
+		notifier: notifier,
+		bus:      bus,
+		log:      logger.For("funding_arb").With(logger.KeyStrategy, "funding_arb"),
+		stopCh:   make(chan struct{}),
+		failures: make(map[string]int),
+		quotes:   make(map[string]map[string]Quote),
+		fetching: make(map[string]bool),
+		closing:  make(map[string]bool),
 	}
+	s.executeTrades.Store(cfg.ExecuteTrades)
+	return s
+}
+
+var _ Strategy = (*FundingArbStrategy)(nil)
+
+func (s *FundingArbStrategy) Name() string {
+	return "funding_arb"
 }
 
 func (s *FundingArbStrategy) Start(ctx context.Context) {
-	log.Println("Starting Funding Arb Strategy...")
-	ticker := time.NewTicker(time.Duration(s.cfg.CheckIntervalMs) * time.Millisecond)
+	s.log.Info("starting strategy")
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	// Reattach to arb pairs opened before a restart so we don't open duplicates
+	if err := s.Recover(); err != nil {
+		s.log.Error("state recovery failed, not starting", logger.Err(err))
+		return
+	}
+	interval := s.config().CheckIntervalMs
+	ticker := time.NewTicker(time.Duration(interval) * time.Millisecond)
 	defer ticker.Stop()
 
+	// Handled between ticks, so they never race with one
+	sub := s.bus.Subscribe("funding_arb", eventBuffer, events.KindFunding, events.KindFill)
+	defer s.bus.Unsubscribe(sub)
+
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Stopping Funding Arb Strategy...")
+			s.log.Info("stopping strategy")
+			s.saveState()
 			return
 		case <-ticker.C:
+			if s.paused.Load() {
+				continue
+			}
 			s.checkOpportunities()
+
+			// Pick up a reloaded interval
+			if ms := s.config().CheckIntervalMs; ms != interval {
+				interval = ms
+				ticker.Reset(time.Duration(interval) * time.Millisecond)
+			}
+		case e := <-sub.Events():
+			switch e := e.(type) {
+			case events.FundingUpdate:
+				if !s.paused.Load() {
+					s.onFundingUpdate(e)
+				}
+			case events.Fill:
+				// Fills are booked even while paused
+				s.onFill(e)
+			}
+		}
+	}
+}
+
+// onFundingUpdate stores a pushed funding rate as the quote of our venues on
+// that exchange, and checks the exit of an open pair on the pair right away
+// rather than at the next tick.
+func (s *FundingArbStrategy) onFundingUpdate(e events.FundingUpdate) {
+	cfg := s.config()
+	exchanges := ownVenues(s.venues.Ready(), cfg.Accounts)
+	for name := range exchanges {
+		if config.ExchangeOf(name) == e.Exchange {
+			s.storeQuotes(name, map[string]float64{e.Symbol: e.Rate}, e.Time)
+		}
+	}
+
+	if !s.executeTrades.Load() {
+		return // the tick logs the exit signal
+	}
+	active := s.activePair(e.Symbol)
+	if active == nil || (config.ExchangeOf(active.LongExchange) != e.Exchange && config.ExchangeOf(active.ShortExchange) != e.Exchange) {
+		return
+	}
+	maxAge := time.Duration(cfg.MaxQuoteAgeMs) * time.Millisecond
+	s.checkExit(active, s.freshRates(e.Symbol, exchanges, maxAge), cfg.ForPair(e.Symbol))
+}
+
+// onFill books a fill of one of our arb legs, opening or closing, to the
+// ledger and to the size the pair holds on that leg. Fills already booked
+// from the placement response are skipped by the ledger.
+func (s *FundingArbStrategy) onFill(e events.Fill) {
+	s.mu.Lock()
+	var pair *state.ArbPair
+	var held *float64
+	var sign float64
+	for _, p := range s.pairs {
+		switch {
+		case p.LongExchange == e.Venue && p.LongOrderID == e.OrderID:
+			held, sign = &p.LongFilled, 1
+		case p.LongExchange == e.Venue && p.LongCloseOrderID == e.OrderID:
+			held, sign = &p.LongFilled, -1
+		case p.ShortExchange == e.Venue && p.ShortOrderID == e.OrderID:
+			held, sign = &p.ShortFilled, 1
+		case p.ShortExchange == e.Venue && p.ShortCloseOrderID == e.OrderID:
+			held, sign = &p.ShortFilled, -1
+		default:
+			continue
 		}
+		pair = p
+		break
 	}
+	s.mu.Unlock()
+	if pair == nil {
+		return
+	}
+
+	l := s.log.With("pair_id", pair.ID, logger.KeyVenue, e.Venue, logger.KeySymbol, e.Symbol, logger.KeyOrderID, e.OrderID)
+	booked, err := s.ledger.RecordFill(pair.ID, e)
+	if err != nil {
+		l.Error("failed to record fill in ledger", "trade_id", e.TradeID, logger.Err(err))
+		return
+	}
+	l.Info("arb leg filled", "side", e.Side, "size", e.Size, "price", e.Price, "fee", e.Fee, "booked", booked)
+	if booked == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	*held += sign * booked
+	s.mu.Unlock()
+	s.saveState()
+}
+
+// ownVenues keeps the venues of the account the strategy trades with on
+// each exchange (the default account unless accounts maps it elsewhere).
+func ownVenues(all map[string]exchange.Exchange, accounts map[string]string) map[string]exchange.Exchange {
+	own := make(map[string]exchange.Exchange, len(all))
+	for name, exc := range all {
+		ex := config.ExchangeOf(name)
+		if name == config.VenueName(ex, accounts[ex]) {
+			own[name] = exc
+		}
+	}
+	return own
 }
 
 func (s *FundingArbStrategy) checkOpportunities() {
-	// Logic to check funding rates across exchanges
-	// For now, just print that we are checking
-	log.Println("Checking funding opportunities...")
+	s.log.Debug("checking funding opportunities")
+	cfg := s.config()
 
-	// Iterate pairs and get funding rates
-	for _, pair := range s.cfg.Pairs {
-		rates := make(map[string]float64)
-		for name, exc := range s.exchanges {
-			rate, err := exc.GetFundingRate(pair)
-			if err != nil {
-				log.Printf("Error getting funding rate from %s for %s: %v", name, pair, err)
-				continue
-			}
-			rates[name] = rate
-			// log.Printf("[%s] %s Funding Rate: %f", name, pair, rate)
+	// Venues that failed setup are left out until they recover
+	exchanges := ownVenues(s.venues.Ready(), cfg.Accounts)
+
+	s.fetchRates(exchanges, cfg)
+
+	s.mu.Lock()
+	s.ratesAt = time.Now()
+	s.mu.Unlock()
+
+	// Spreads are only computed from quotes close enough in time
+	maxAge := time.Duration(cfg.MaxQuoteAgeMs) * time.Millisecond
+	for _, pair := range cfg.Pairs {
+		rates := s.freshRates(pair, exchanges, maxAge)
+
+		params := cfg.ForPair(pair)
+		active := s.activePair(pair)
+		if active != nil {
+			s.checkExit(active, rates, params)
 		}
 
-		// Calculate max difference
-		if len(rates) < 2 {
+		// Calculate max difference among the venues allowed for this pair
+		allowed := make(map[string]float64, len(rates))
+		for name, rate := range rates {
+			if params.AllowsVenue(name) {
+				allowed[name] = rate
+			}
+		}
+		if len(allowed) < 2 {
 			continue
 		}
 
@@ -66,7 +275,7 @@ func (s *FundingArbStrategy) checkOpportunities() {
 		var maxName, minName string
 		first := true
 
-		for name, rate := range rates {
+		for name, rate := range allowed {
 			if first {
 				maxRate = rate
 				minRate = rate
@@ -86,71 +295,538 @@ func (s *FundingArbStrategy) checkOpportunities() {
 		}
 
 		diff := maxRate - minRate
-		if diff >= s.cfg.MinFundingDiff {
-			log.Printf("OPPORTUNITY FOUND [%s]: Buy %s on %s (Rate: %f) / Sell on %s (Rate: %f) | Diff: %f",
-				pair, pair, minName, minRate, maxName, maxRate, diff)
+		metrics.BestSpread.WithLabelValues(pair).Set(diff)
+		if diff >= params.MinFundingDiff {
+			s.log.Info("opportunity found", logger.KeySymbol, pair,
+				"long_venue", minName, "long_rate", minRate,
+				"short_venue", maxName, "short_rate", maxRate, "diff", diff)
 
-			if s.cfg.ExecuteTrades {
-				s.executeArbitrage(pair, minName, maxName)
+			opp := Opportunity{
+				Symbol:        pair,
+				LongExchange:  minName,
+				ShortExchange: maxName,
+				LongRate:      minRate,
+				ShortRate:     maxRate,
+				Diff:          diff,
+				DetectedAt:    time.Now(),
 			}
+
+			if active != nil {
+				s.log.Info("arb pair already open, skipping", logger.KeySymbol, pair,
+					"pair_id", active.ID, "long_venue", active.LongExchange, "short_venue", active.ShortExchange)
+			} else if s.executeTrades.Load() {
+				opp.Executed = s.executeArbitrage(pair, minName, maxName, diff, params)
+			}
+			s.recordOpportunity(opp)
 		} else {
-			log.Printf("[%s] Best Diff: %f (Threshold: %f) - No Opportunity", pair, diff, s.cfg.MinFundingDiff)
+			s.log.Debug("no opportunity", logger.KeySymbol, pair, "diff", diff, "threshold", params.MinFundingDiff)
 		}
 	}
 }
 
-func (s *FundingArbStrategy) executeArbitrage(symbol, longExchange, shortExchange string) {
-	// Fixed size for testing - TODO: Make configurable or dynamic
-	size := 0.01 // e.g. 0.01 ETH
+// executeArbitrage opens a pair and reports whether both legs were placed.
+func (s *FundingArbStrategy) executeArbitrage(symbol, longExchange, shortExchange string, diff float64, params config.PairParams) bool {
+	pairID := fmt.Sprintf("%s-%d", symbol, time.Now().UnixNano())
+	l := s.log.With(logger.KeyCorrelationID, logger.NewCorrelationID(), logger.KeySymbol, symbol, "pair_id", pairID)
+
+	size, ok := s.legSize(l, symbol, longExchange, shortExchange, params)
+	if !ok {
+		return false
+	}
 
-	log.Printf("Executing Arbitrage: Long %f %s on %s, Short %f %s on %s",
-		size, symbol, longExchange, size, symbol, shortExchange)
+	l.Info("executing arbitrage", "size", size, "long_venue", longExchange, "short_venue", shortExchange,
+		"leverage", params.Leverage)
+
+	var longID, shortID string
+	var longFilled, shortFilled float64
+	var wg sync.WaitGroup
+	wg.Add(2)
 
 	// Execute Long
 	go func() {
-		price, err := s.exchanges[longExchange].GetPrice(symbol)
-		if err != nil {
-			log.Printf("Failed to get price from %s: %v", longExchange, err)
-			return
-		}
+		defer wg.Done()
 		// Buy with 1% slippage
-		limitPrice := price * 1.01
-
-		_, err = s.exchanges[longExchange].PlaceOrder(&exchange.OrderRequest{
-			Symbol: symbol,
-			Side:   "buy",
-			Size:   size,
-			Type:   "limit",
-			Price:  limitPrice,
+		longID, longFilled = s.placeLeg(l, pairID, longExchange, symbol, "buy", size, 1.01, false)
+	}()
+
+	// Execute Short
+	go func() {
+		defer wg.Done()
+		// Sell with 1% slippage
+		shortID, shortFilled = s.placeLeg(l, pairID, shortExchange, symbol, "sell", size, 0.99, false)
+	}()
+
+	wg.Wait()
+
+	if longID == "" && shortID == "" {
+		s.notifier.Warn("Arbitrage failed", "both legs failed, nothing opened", map[string]any{
+			"pair_id": pairID, "symbol": symbol, "long": longExchange, "short": shortExchange,
+		})
+		return false
+	}
+
+	pair := &state.ArbPair{
+		ID:            pairID,
+		Symbol:        symbol,
+		LongExchange:  longExchange,
+		ShortExchange: shortExchange,
+		Size:          size,
+		LongOrderID:   longID,
+		ShortOrderID:  shortID,
+		EntryDiff:     diff,
+		Status:        state.StatusOpen,
+		OpenedAt:      time.Now(),
+		LongFilled:    longFilled,
+		ShortFilled:   shortFilled,
+	}
+	if longID == "" || shortID == "" {
+		pair.Status = state.StatusUnhedged
+		l.Warn("arb pair is unhedged - one leg failed, manual intervention required")
+		s.notifier.Critical("Arb pair unhedged", "one leg failed, manual intervention required", map[string]any{
+			"pair_id": pairID, "symbol": symbol, "long": longExchange, "long_order": longID,
+			"short": shortExchange, "short_order": shortID, "size": size,
+		})
+	} else {
+		s.notifier.Info("Arbitrage executed", "", map[string]any{
+			"pair_id": pairID, "symbol": symbol, "long": longExchange, "short": shortExchange,
+			"size": size, "diff": diff,
 		})
+	}
+
+	s.mu.Lock()
+	s.pairs = append(s.pairs, pair)
+	s.mu.Unlock()
+	s.saveState()
+	return longID != "" && shortID != ""
+}
+
+// legSize works out the size of each leg from max_notional and checks that
+// both venues have enough margin for it at the pair's leverage.
+func (s *FundingArbStrategy) legSize(l *slog.Logger, symbol, longExchange, shortExchange string, params config.PairParams) (float64, bool) {
+	exc, _ := s.venues.Get(longExchange)
+	price, err := exc.GetPrice(symbol)
+	if err != nil {
+		l.Error("failed to get price for sizing", logger.KeyVenue, longExchange, logger.Err(err))
+		return 0, false
+	}
+
+	size := testSize
+	if params.MaxNotional > 0 {
+		size = math.Floor(params.MaxNotional/price*1e4) / 1e4
+		if size <= 0 {
+			l.Warn("max_notional too small for one lot, skipping", "max_notional", params.MaxNotional, "price", price)
+			return 0, false
+		}
+	}
+
+	margin := size * price / params.Leverage
+	for _, name := range []string{longExchange, shortExchange} {
+		exc, _ := s.venues.Get(name)
+		balance, err := exc.GetBalance("USDC")
 		if err != nil {
-			log.Printf("Failed to place Long on %s: %v", longExchange, err)
-		} else {
-			log.Printf("Placed Long on %s at %f", longExchange, limitPrice)
+			// Not every venue reports balances yet; let the order decide
+			l.Warn("cannot check margin", logger.KeyVenue, name, logger.Err(err))
+			continue
 		}
-	}()
+		if balance < margin {
+			l.Warn("insufficient margin, skipping", logger.KeyVenue, name, "balance", balance, "required", margin)
+			return 0, false
+		}
+	}
+	return size, true
+}
 
-	// Execute Short
+// checkExit closes an open arb pair once the spread between its legs
+// drops below the exit threshold.
+func (s *FundingArbStrategy) checkExit(p *state.ArbPair, rates map[string]float64, params config.PairParams) {
+	s.mu.Lock()
+	status, closing := p.Status, s.closing[p.ID]
+	s.mu.Unlock()
+	if status != state.StatusOpen || closing {
+		return // unhedged pairs need manual attention
+	}
+	longRate, ok := rates[p.LongExchange]
+	if !ok {
+		return
+	}
+	shortRate, ok := rates[p.ShortExchange]
+	if !ok {
+		return
+	}
+
+	spread := shortRate - longRate
+	if spread >= params.ExitFundingDiff {
+		return
+	}
+
+	s.log.Info("spread below exit threshold", logger.KeySymbol, p.Symbol, "pair_id", p.ID,
+		"spread", spread, "exit_threshold", params.ExitFundingDiff)
+	if !s.executeTrades.Load() {
+		return
+	}
+	s.closeArbitrage(p)
+}
+
+// closeArbitrage unwinds both legs with reduce-only orders. Like opening,
+// the pair is marked closed once both orders are accepted. Exit checks run
+// from both the tick and funding updates, so a pair already being closed is
+// skipped.
+func (s *FundingArbStrategy) closeArbitrage(p *state.ArbPair) {
+	s.mu.Lock()
+	if p.Status != state.StatusOpen || s.closing[p.ID] {
+		s.mu.Unlock()
+		return
+	}
+	s.closing[p.ID] = true
+	s.mu.Unlock()
+
+	l := s.log.With(logger.KeyCorrelationID, logger.NewCorrelationID(), logger.KeySymbol, p.Symbol, "pair_id", p.ID)
+	l.Info("closing arbitrage", "size", p.Size, "long_venue", p.LongExchange, "short_venue", p.ShortExchange)
+
+	var longID, shortID string
+	var longFilled, shortFilled float64
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		longID, longFilled = s.placeLeg(l, p.ID, p.LongExchange, p.Symbol, "sell", p.Size, 0.99, true)
+	}()
 	go func() {
-		price, err := s.exchanges[shortExchange].GetPrice(symbol)
+		defer wg.Done()
+		shortID, shortFilled = s.placeLeg(l, p.ID, p.ShortExchange, p.Symbol, "buy", p.Size, 1.01, true)
+	}()
+	wg.Wait()
+
+	s.mu.Lock()
+	delete(s.closing, p.ID)
+	p.LongFilled -= longFilled
+	p.ShortFilled -= shortFilled
+	if longID != "" {
+		p.LongCloseOrderID = longID
+	}
+	if shortID != "" {
+		p.ShortCloseOrderID = shortID
+	}
+	switch {
+	case longID != "" && shortID != "":
+		p.Status = state.StatusClosed
+		p.ClosedAt = time.Now()
+	case longID != "" || shortID != "":
+		p.Status = state.StatusUnhedged
+	}
+	status := p.Status
+	s.mu.Unlock()
+
+	switch status {
+	case state.StatusClosed:
+		s.notifier.Info("Arbitrage closed", "", map[string]any{
+			"pair_id": p.ID, "symbol": p.Symbol, "long": p.LongExchange, "short": p.ShortExchange,
+		})
+	case state.StatusUnhedged:
+		l.Warn("arb pair is unhedged - one closing leg failed, manual intervention required")
+		s.notifier.Critical("Arb pair unhedged", "one closing leg failed, manual intervention required", map[string]any{
+			"pair_id": p.ID, "symbol": p.Symbol, "long_close_order": longID, "short_close_order": shortID,
+		})
+	}
+	s.saveState()
+}
+
+// placeLeg places one leg at the current price adjusted by priceFactor and
+// returns the order ID, or "" if the order failed, and the size that filled
+// on placement. Immediate fills are booked to the ledger under pairID.
+func (s *FundingArbStrategy) placeLeg(l *slog.Logger, pairID, exchangeName, symbol, side string, size, priceFactor float64, reduceOnly bool) (string, float64) {
+	exc, _ := s.venues.Get(exchangeName)
+	l = l.With(logger.KeyVenue, exchangeName, "side", side)
+
+	price, err := exc.GetPrice(symbol)
+	if err != nil {
+		l.Error("failed to get price", logger.Err(err))
+		s.notifyLegFailed(pairID, exchangeName, symbol, side, err)
+		return "", 0
+	}
+	limitPrice := price * priceFactor
+
+	res, err := exc.PlaceOrder(&exchange.OrderRequest{
+		Symbol:     symbol,
+		Side:       side,
+		Size:       size,
+		Type:       "limit",
+		Price:      limitPrice,
+		ReduceOnly: reduceOnly,
+	})
+	if err != nil {
+		l.Error("failed to place order", "error_class", exchange.Class(err), logger.Err(err))
+		s.notifyLegFailed(pairID, exchangeName, symbol, side, err)
+		return "", 0
+	}
+
+	l.Info("placed order", "price", limitPrice, logger.KeyOrderID, res.OrderID, "status", res.Status)
+
+	var filled float64
+	if res.FilledSize > 0 {
+		filled, err = s.ledger.RecordTrade(pairID, exchangeName, symbol, side, res.FilledSize, res.AvgPrice, res.Fee, res.OrderID)
 		if err != nil {
-			log.Printf("Failed to get price from %s: %v", shortExchange, err)
-			return
+			l.Error("failed to record fill in ledger", logger.KeyOrderID, res.OrderID, logger.Err(err))
 		}
-		// Sell with 1% slippage
-		limitPrice := price * 0.99
-
-		_, err = s.exchanges[shortExchange].PlaceOrder(&exchange.OrderRequest{
-			Symbol: symbol,
-			Side:   "sell",
-			Size:   size,
-			Type:   "limit",
-			Price:  limitPrice,
+	}
+	return res.OrderID, filled
+}
+
+func (s *FundingArbStrategy) notifyLegFailed(pairID, exchangeName, symbol, side string, err error) {
+	s.notifier.Critical("Arb leg failed", err.Error(), map[string]any{
+		"pair_id": pairID, "venue": exchangeName, "symbol": symbol, "side": side,
+		"error_class": exchange.Class(err),
+	})
+}
+
+// venueFailed counts a failed call and alerts once the venue looks down.
+// Errors that say nothing about the venue's health (not implemented, our own
+// rate limit) aren't counted.
+func (s *FundingArbStrategy) venueFailed(name string, err error) {
+	if !exchange.Retryable(err) && exchange.Class(err) != "unknown" {
+		return
+	}
+	s.failures[name]++
+	if s.failures[name] == venueDownAfter {
+		s.notifier.Notify(notify.Event{
+			Severity: notify.SeverityWarning,
+			Title:    "Venue API down",
+			Message:  fmt.Sprintf("%s: %d consecutive failures, last: %v", name, venueDownAfter, err),
+			Fields:   map[string]any{"venue": name},
+			DedupKey: "venue-down/" + name,
 		})
+	}
+}
+
+// venueOK resets the failure count and reports recovery of a down venue.
+func (s *FundingArbStrategy) venueOK(name string) {
+	if s.failures[name] >= venueDownAfter {
+		s.notifier.Info("Venue API recovered", name, map[string]any{"venue": name})
+	}
+	s.failures[name] = 0
+}
+
+// activePair returns the active arb pair for symbol, if any.
+func (s *FundingArbStrategy) activePair(symbol string) *state.ArbPair {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.pairs {
+		if p.Symbol == symbol && p.Active() {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *FundingArbStrategy) recordOpportunity(opp Opportunity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.opportunities = append(s.opportunities, opp)
+	if len(s.opportunities) > maxOpportunities {
+		s.opportunities = s.opportunities[len(s.opportunities)-maxOpportunities:]
+	}
+}
+
+// Opportunities returns the most recently detected opportunities, newest last.
+func (s *FundingArbStrategy) Opportunities() []Opportunity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Opportunity(nil), s.opportunities...)
+}
+
+// Rates returns the latest funding rate quotes by pair and exchange, and
+// when the last check ran.
+func (s *FundingArbStrategy) Rates() (map[string]map[string]Quote, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	quotes := make(map[string]map[string]Quote, len(s.quotes))
+	for pair, byExchange := range s.quotes {
+		quotes[pair] = maps.Clone(byExchange)
+	}
+	return quotes, s.ratesAt
+}
+
+func (s *FundingArbStrategy) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	params := make(map[string]config.PairParams, len(s.cfg.Pairs))
+	for _, pair := range s.cfg.Pairs {
+		params[pair] = s.cfg.ForPair(pair)
+	}
+
+	return Status{
+		Name:          s.Name(),
+		Running:       s.running.Load(),
+		Paused:        s.paused.Load(),
+		ExecuteTrades: s.executeTrades.Load(),
+		LastRun:       s.ratesAt,
+		Details: FundingArbDetails{
+			Pairs:    s.cfg.Pairs,
+			Params:   params,
+			ArbPairs: s.copyPairs(),
+			Orphans:  append([]*state.Orphan(nil), s.orphans...),
+		},
+	}
+}
+
+func (s *FundingArbStrategy) Pause() {
+	s.paused.Store(true)
+	s.log.Info("strategy paused")
+}
+
+func (s *FundingArbStrategy) Resume() {
+	s.paused.Store(false)
+	s.log.Info("strategy resumed")
+}
+
+func (s *FundingArbStrategy) CancelRestingOrders() {
+	// Order IDs of arb pair legs, by exchange. A closed pair's reduce-only
+	// orders may still rest, so every pair counts; only IDs still open on
+	// the venue are cancelled.
+	legOrders := make(map[string]map[string]bool)
+	s.mu.Lock()
+	for _, p := range s.pairs {
+		for _, leg := range []struct{ name, id string }{
+			{p.LongExchange, p.LongOrderID}, {p.ShortExchange, p.ShortOrderID},
+			{p.LongExchange, p.LongCloseOrderID}, {p.ShortExchange, p.ShortCloseOrderID},
+		} {
+			if leg.id == "" {
+				continue
+			}
+			if legOrders[leg.name] == nil {
+				legOrders[leg.name] = make(map[string]bool)
+			}
+			legOrders[leg.name][leg.id] = true
+		}
+	}
+	s.mu.Unlock()
+
+	for name, ids := range legOrders {
+		exc, ok := s.venues.Get(name)
+		if !ok {
+			s.log.Warn("exchange not enabled, leaving orders in place", logger.KeyVenue, name, "orders", len(ids))
+			continue
+		}
+		orders, err := exc.GetOpenOrders()
 		if err != nil {
-			log.Printf("Failed to place Short on %s: %v", shortExchange, err)
+			s.log.Error("cannot list open orders, leaving orders in place",
+				logger.KeyVenue, name, "orders", len(ids), logger.Err(err))
+			continue
+		}
+		for _, o := range orders {
+			if !ids[o.OrderID] {
+				continue
+			}
+			if err := exc.CancelOrder(o.Symbol, o.OrderID); err != nil {
+				s.log.Error("failed to cancel order", logger.KeyVenue, name, logger.KeyOrderID, o.OrderID, logger.Err(err))
+				continue
+			}
+			s.log.Info("cancelled resting order", logger.KeyVenue, name, logger.KeySymbol, o.Symbol, logger.KeyOrderID, o.OrderID)
+		}
+	}
+}
+
+func (s *FundingArbStrategy) config() config.FundingArbConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg
+}
+
+// UpdateConfig applies a reloaded config from the next tick on. Open arb
+// pairs are never touched, including those on pairs no longer configured.
+func (s *FundingArbStrategy) UpdateConfig(cfg config.FundingArbConfig) {
+	s.mu.Lock()
+	old := s.cfg
+	s.cfg = cfg
+
+	var kept []*state.ArbPair
+	for symbol := range s.quotes {
+		if !slices.Contains(cfg.Pairs, symbol) {
+			delete(s.quotes, symbol)
+		}
+	}
+	for _, p := range s.pairs {
+		if p.Active() && !slices.Contains(cfg.Pairs, p.Symbol) {
+			kept = append(kept, p)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, p := range kept {
+		s.log.Warn("pair removed from config, leaving open arb pair in place",
+			logger.KeySymbol, p.Symbol, "pair_id", p.ID)
+	}
+	if cfg.ExecuteTrades != old.ExecuteTrades {
+		s.SetExecuteTrades(cfg.ExecuteTrades)
+	}
+	if cfg.Enabled != old.Enabled {
+		if cfg.Enabled {
+			s.Resume()
 		} else {
-			log.Printf("Placed Short on %s at %f", shortExchange, limitPrice)
+			s.Pause()
 		}
-	}()
+	}
+}
+
+// SetExecuteTrades toggles whether detected opportunities are traded.
+func (s *FundingArbStrategy) SetExecuteTrades(enabled bool) {
+	s.executeTrades.Store(enabled)
+	s.log.Info("execute_trades changed", "enabled", enabled)
+}
+
+// PairFor returns the ID of the arb pair that held a leg on symbol at
+// exchangeName at the given time, or "" if there was none.
+func (s *FundingArbStrategy) PairFor(exchangeName, symbol string, at time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.pairs {
+		if p.Symbol != symbol || (p.LongExchange != exchangeName && p.ShortExchange != exchangeName) {
+			continue
+		}
+		if at.Before(p.OpenedAt) || (!p.ClosedAt.IsZero() && at.After(p.ClosedAt)) {
+			continue
+		}
+		return p.ID
+	}
+	return ""
+}
+
+// LatestRate returns the funding rate seen for symbol on exchangeName in
+// the last check.
+func (s *FundingArbStrategy) LatestRate(exchangeName, symbol string) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.quotes[symbol][exchangeName]
+	return q.Rate, ok
+}
+
+// copyPairs returns a deep copy of the arb pairs, which are modified in
+// place under s.mu; s.mu held.
+func (s *FundingArbStrategy) copyPairs() []*state.ArbPair {
+	pairs := make([]*state.ArbPair, len(s.pairs))
+	for i, p := range s.pairs {
+		c := *p
+		pairs[i] = &c
+	}
+	return pairs
+}
+
+func (s *FundingArbStrategy) saveState() {
+	s.mu.Lock()
+	st := &state.State{
+		ArbPairs: s.copyPairs(),
+		Orphans:  s.orphans, // replaced, never modified
+	}
+	s.mu.Unlock()
+
+	if err := s.store.Save(st); err != nil {
+		s.log.Error("failed to persist state", logger.Err(err))
+	}
 }
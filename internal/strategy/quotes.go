@@ -0,0 +1,128 @@
+package strategy
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"arbitrage-bot/internal/config"
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+)
+
+// Quote is a funding rate and when it was received.
+type Quote struct {
+	Rate float64   `json:"rate"`
+	At   time.Time `json:"at"`
+}
+
+// fetchRates queries every venue concurrently, one request each for all
+// pairs, and waits until they answered or the fetch timeout passed. Venues
+// that answer late still update the quotes, for later ticks to use while
+// they are fresh. A venue whose previous request is still running is not
+// queried again.
+func (s *FundingArbStrategy) fetchRates(exchanges map[string]exchange.Exchange, cfg config.FundingArbConfig) {
+	type result struct {
+		name string
+		err  error
+	}
+	// Buffered so late answers don't block once nobody is reading
+	results := make(chan result, len(exchanges))
+
+	pending := make(map[string]bool, len(exchanges))
+	for name, exc := range exchanges {
+		if !s.startFetch(name) {
+			s.log.Debug("previous funding rate request still running", logger.KeyVenue, name)
+			continue
+		}
+		pending[name] = true
+		go func() {
+			defer s.endFetch(name)
+			rates, err := exc.GetFundingRates(cfg.Pairs)
+			if err == nil {
+				s.storeQuotes(name, rates, time.Now())
+			}
+			results <- result{name: name, err: err}
+		}()
+	}
+
+	timeout := time.Duration(cfg.FetchTimeoutMs) * time.Millisecond
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.name)
+			if r.err != nil {
+				s.log.Warn("failed to get funding rates", logger.KeyVenue, r.name, logger.Err(r.err))
+				s.venueFailed(r.name, r.err)
+				continue
+			}
+			s.venueOK(r.name)
+		case <-timer.C:
+			for name := range pending {
+				s.log.Warn("funding rates not received in time", logger.KeyVenue, name, "timeout", timeout)
+				s.venueFailed(name, fmt.Errorf("%w: no funding rates within %s", exchange.ErrTransient, timeout))
+			}
+			return
+		}
+	}
+}
+
+func (s *FundingArbStrategy) startFetch(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetching[name] {
+		return false
+	}
+	s.fetching[name] = true
+	return true
+}
+
+func (s *FundingArbStrategy) endFetch(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.fetching, name)
+}
+
+func (s *FundingArbStrategy) storeQuotes(name string, rates map[string]float64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for pair, rate := range rates {
+		// The pair may have been removed by a reload since the request
+		if !slices.Contains(s.cfg.Pairs, pair) {
+			continue
+		}
+		if s.quotes[pair] == nil {
+			s.quotes[pair] = make(map[string]Quote)
+		}
+		s.quotes[pair][name] = Quote{Rate: rate, At: at}
+	}
+}
+
+// freshRates returns the rates for pair on the given venues, leaving out
+// quotes older than maxAge.
+func (s *FundingArbStrategy) freshRates(pair string, exchanges map[string]exchange.Exchange, maxAge time.Duration) map[string]float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	rates := make(map[string]float64, len(exchanges))
+	for name := range exchanges {
+		q, ok := s.quotes[pair][name]
+		if !ok {
+			s.log.Debug("no funding rate", logger.KeyVenue, name, logger.KeySymbol, pair)
+			continue
+		}
+		if age := now.Sub(q.At); age > maxAge {
+			s.log.Debug("discarding stale funding rate", logger.KeyVenue, name, logger.KeySymbol, pair, "age", age)
+			continue
+		}
+		rates[name] = q.Rate
+		s.log.Debug("funding rate", logger.KeyVenue, name, logger.KeySymbol, pair, "rate", q.Rate)
+	}
+	return rates
+}
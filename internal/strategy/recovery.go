@@ -0,0 +1,190 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/state"
+)
+
+// venueSnapshot is the live account state of one exchange at startup.
+type venueSnapshot struct {
+	positions map[string]*exchange.Position // by symbol
+	orders    map[string]*exchange.Order    // by order ID
+}
+
+// Recover loads the persisted strategy state and reconciles it against the
+// live positions and open orders on each exchange. Arb pairs whose legs are
+// still live are reattached, pairs whose legs are all gone are closed, and
+// positions or orders that don't belong to any known pair are flagged as
+// orphans.
+func (s *FundingArbStrategy) Recover() error {
+	st, err := s.store.Load()
+	if err != nil {
+		return err
+	}
+
+	// Only our own accounts are scanned for orphans, plus any venue an
+	// active pair was opened on before the accounts were changed.
+	all := s.venues.All()
+	venues := ownVenues(all, s.config().Accounts)
+	for _, pair := range st.ArbPairs {
+		if !pair.Active() {
+			continue
+		}
+		for _, name := range []string{pair.LongExchange, pair.ShortExchange} {
+			if exc, ok := all[name]; ok {
+				venues[name] = exc
+			}
+		}
+	}
+
+	// Exchanges we couldn't query are left out; pairs touching them are
+	// kept as-is since we can't prove they are gone.
+	snapshots := make(map[string]*venueSnapshot)
+	for name, exc := range venues {
+		snap, err := loadVenueSnapshot(exc)
+		if err != nil {
+			s.log.Warn("recovery: cannot verify venue state", logger.KeyVenue, name, logger.Err(err))
+			continue
+		}
+		snapshots[name] = snap
+	}
+
+	// Venue/symbol legs and order IDs claimed by an active pair
+	claimedLegs := make(map[string]bool)
+	claimedOrders := make(map[string]bool)
+
+	reattached := 0
+	for _, pair := range st.ArbPairs {
+		if !pair.Active() {
+			continue
+		}
+
+		longLive, longKnown := legStatus(snapshots[pair.LongExchange], pair.Symbol, pair.LongOrderID, 1)
+		shortLive, shortKnown := legStatus(snapshots[pair.ShortExchange], pair.Symbol, pair.ShortOrderID, -1)
+
+		switch {
+		case longKnown && shortKnown && !longLive && !shortLive:
+			pair.Status = state.StatusClosed
+			pair.ClosedAt = time.Now()
+			s.log.Info("recovery: arb pair has no live legs, marking closed",
+				"pair_id", pair.ID, logger.KeySymbol, pair.Symbol)
+			continue
+		case (longKnown && !longLive) || (shortKnown && !shortLive):
+			pair.Status = state.StatusUnhedged
+			s.log.Warn("recovery: arb pair is missing a leg", "pair_id", pair.ID, logger.KeySymbol, pair.Symbol,
+				"long_venue", pair.LongExchange, "long_live", longLive,
+				"short_venue", pair.ShortExchange, "short_live", shortLive)
+			s.notifier.Critical("Arb pair unhedged after restart", "one leg is no longer live", map[string]any{
+				"pair_id": pair.ID, "symbol": pair.Symbol,
+				"long": pair.LongExchange, "long_live": longLive,
+				"short": pair.ShortExchange, "short_live": shortLive,
+			})
+		default:
+			s.log.Info("recovery: reattached arb pair", "pair_id", pair.ID, logger.KeySymbol, pair.Symbol,
+				"long_venue", pair.LongExchange, "short_venue", pair.ShortExchange)
+		}
+		reattached++
+
+		claimedLegs[legKey(pair.LongExchange, pair.Symbol)] = true
+		claimedLegs[legKey(pair.ShortExchange, pair.Symbol)] = true
+		claimedOrders[legKey(pair.LongExchange, pair.LongOrderID)] = true
+		claimedOrders[legKey(pair.ShortExchange, pair.ShortOrderID)] = true
+		claimedOrders[legKey(pair.LongExchange, pair.LongCloseOrderID)] = true
+		claimedOrders[legKey(pair.ShortExchange, pair.ShortCloseOrderID)] = true
+	}
+
+	var orphans []*state.Orphan
+	now := time.Now()
+	for name, snap := range snapshots {
+		for symbol, pos := range snap.positions {
+			if claimedLegs[legKey(name, symbol)] {
+				continue
+			}
+			s.log.Warn("recovery: orphaned position", logger.KeyVenue, name, logger.KeySymbol, symbol, "size", pos.Size)
+			orphans = append(orphans, &state.Orphan{
+				Exchange:   name,
+				Kind:       "position",
+				Symbol:     symbol,
+				Size:       pos.Size,
+				DetectedAt: now,
+			})
+		}
+		for id, order := range snap.orders {
+			if claimedOrders[legKey(name, id)] {
+				continue
+			}
+			s.log.Warn("recovery: orphaned order", logger.KeyVenue, name, logger.KeySymbol, order.Symbol,
+				logger.KeyOrderID, id, "side", order.Side, "size", order.Size, "price", order.Price)
+			orphans = append(orphans, &state.Orphan{
+				Exchange:   name,
+				Kind:       "order",
+				Symbol:     order.Symbol,
+				OrderID:    id,
+				Size:       order.Size,
+				DetectedAt: now,
+			})
+		}
+	}
+
+	s.mu.Lock()
+	s.pairs = st.ArbPairs
+	s.orphans = orphans
+	s.mu.Unlock()
+	s.saveState()
+
+	s.log.Info("recovery complete", "active_pairs", reattached, "orphans", len(orphans))
+	if len(orphans) > 0 {
+		s.notifier.Warn("Orphaned positions or orders", "found on restart, not matched to any arb pair",
+			map[string]any{"orphans": len(orphans)})
+	}
+	return nil
+}
+
+func loadVenueSnapshot(exc exchange.Exchange) (*venueSnapshot, error) {
+	positions, err := exc.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	orders, err := exc.GetOpenOrders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	snap := &venueSnapshot{
+		positions: make(map[string]*exchange.Position, len(positions)),
+		orders:    make(map[string]*exchange.Order, len(orders)),
+	}
+	for _, p := range positions {
+		snap.positions[p.Symbol] = p
+	}
+	for _, o := range orders {
+		snap.orders[o.OrderID] = o
+	}
+	return snap, nil
+}
+
+// legStatus reports whether a pair leg is live on the exchange: either a
+// position in the expected direction (sign 1 long, -1 short) or its order
+// still resting. known is false when the exchange couldn't be queried.
+func legStatus(snap *venueSnapshot, symbol, orderID string, sign float64) (live, known bool) {
+	if snap == nil {
+		return false, false
+	}
+	if pos, ok := snap.positions[symbol]; ok && pos.Size*sign > 0 {
+		return true, true
+	}
+	if orderID != "" {
+		if _, ok := snap.orders[orderID]; ok {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func legKey(exchangeName, id string) string {
+	return exchangeName + "/" + id
+}
@@ -0,0 +1,29 @@
+package strategy
+
+import (
+	"context"
+	"time"
+)
+
+// Strategy is the control surface shared by all strategies.
+type Strategy interface {
+	Name() string
+	Start(ctx context.Context)
+	Status() Status
+	Pause()
+	Resume()
+
+	// CancelRestingOrders cancels orders the strategy placed that are
+	// still resting on the books. Called on shutdown.
+	CancelRestingOrders()
+}
+
+// Status is a point-in-time view of a strategy.
+type Status struct {
+	Name          string    `json:"name"`
+	Running       bool      `json:"running"`
+	Paused        bool      `json:"paused"`
+	ExecuteTrades bool      `json:"execute_trades"`
+	LastRun       time.Time `json:"last_run,omitzero"`
+	Details       any       `json:"details,omitempty"`
+}
@@ -2,28 +2,60 @@ package strategy
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"arbitrage-bot/internal/config"
 	"arbitrage-bot/internal/exchange"
+	"arbitrage-bot/internal/logger"
+	"arbitrage-bot/internal/notify"
 )
 
 type XPFarmingStrategy struct {
-	cfg       config.XPFarmingConfig
-	exchanges map[string]exchange.Exchange
+	venues   *exchange.Registry
+	notifier *notify.Router
+	log      *slog.Logger
+
+	running atomic.Bool
+	paused  atomic.Bool
+	lastRun atomic.Int64 // unix nanos
+
+	mu      sync.Mutex
+	cfg     config.XPFarmingConfig // replaced on hot reload
+	resting []restingOrder         // orders that didn't fill on placement
 }
 
-func NewXPFarmingStrategy(cfg config.XPFarmingConfig, exchanges map[string]exchange.Exchange) *XPFarmingStrategy {
+type restingOrder struct {
+	exchange string
+	symbol   string
+	orderID  string
+}
+
+// Number of resting orders remembered for cancellation on shutdown
+const maxRestingOrders = 20
+
+func NewXPFarmingStrategy(cfg config.XPFarmingConfig, venues *exchange.Registry, notifier *notify.Router) *XPFarmingStrategy {
 	return &XPFarmingStrategy{
-		cfg:       cfg,
-		exchanges: exchanges,
+		cfg:      cfg,
+		venues:   venues,
+		notifier: notifier,
+		log:      logger.For("xp_farming").With(logger.KeyStrategy, "xp_farming"),
 	}
 }
 
+var _ Strategy = (*XPFarmingStrategy)(nil)
+
+func (s *XPFarmingStrategy) Name() string {
+	return "xp_farming"
+}
+
 func (s *XPFarmingStrategy) Start(ctx context.Context) {
-	log.Println("Starting XP Farming Strategy...")
+	s.log.Info("starting strategy")
+	s.running.Store(true)
+	defer s.running.Store(false)
 
 	// Random interval to avoid detection (e.g., between 5 to 15 minutes)
 	// For testing, we use shorter intervals
@@ -36,10 +68,13 @@ func (s *XPFarmingStrategy) Start(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Stopping XP Farming Strategy...")
+			s.log.Info("stopping strategy")
 			return
 		case <-timer.C:
-			s.executeFarming()
+			if !s.paused.Load() {
+				s.executeFarming()
+				s.lastRun.Store(time.Now().UnixNano())
+			}
 			timer.Reset(s.randomDuration(minInterval, maxInterval))
 		}
 	}
@@ -55,27 +90,34 @@ func (s *XPFarmingStrategy) executeFarming() {
 	// For MVP, we'll just pick Hyperliquid and execute a wash trade (Buy then Sell)
 	// WARNING: This incurs fees. Ensure config allows this.
 
-	targetExchange := "hyperliquid" // TODO: Randomize or config
-	exc, ok := s.exchanges[targetExchange]
+	cfg := s.config()
+	targetExchange := config.VenueName("hyperliquid", cfg.Account)
+	exc, ok := s.venues.Get(targetExchange)
 	if !ok {
-		log.Printf("XP Farming: Exchange %s not found", targetExchange)
+		s.log.Error("exchange not found", logger.KeyVenue, targetExchange)
+		return
+	}
+	if !s.venues.IsReady(targetExchange) {
+		s.log.Warn("exchange not ready, skipping", logger.KeyVenue, targetExchange)
 		return
 	}
 
 	symbol := "ETH" // TODO: Configurable
 	size := 0.01    // TODO: Configurable based on target volume
 
-	log.Printf("XP Farming: Executing wash trade on %s for %f %s", targetExchange, size, symbol)
+	l := s.log.With(logger.KeyCorrelationID, logger.NewCorrelationID(),
+		logger.KeyVenue, targetExchange, logger.KeySymbol, symbol)
+	l.Info("executing wash trade", "size", size)
 
 	// 1. Get Price
 	price, err := exc.GetPrice(symbol)
 	if err != nil {
-		log.Printf("XP Farming: Failed to get price: %v", err)
+		l.Error("failed to get price", logger.Err(err))
 		return
 	}
 
 	// 2. Place Buy Order
-	buyPrice := price * (1 + s.cfg.MaxSlippage)
+	buyPrice := price * (1 + cfg.MaxSlippage)
 	buyReq := &exchange.OrderRequest{
 		Symbol:     symbol,
 		Side:       "buy",
@@ -87,17 +129,18 @@ func (s *XPFarmingStrategy) executeFarming() {
 
 	buyRes, err := exc.PlaceOrder(buyReq)
 	if err != nil {
-		log.Printf("XP Farming: Buy failed: %v", err)
+		l.Error("buy failed", logger.Err(err))
 		return
 	}
-	log.Printf("XP Farming: Buy placed (Status: %s, ID: %s)", buyRes.Status, buyRes.OrderID)
+	l.Info("buy placed", "status", buyRes.Status, logger.KeyOrderID, buyRes.OrderID)
+	s.trackResting(targetExchange, symbol, buyRes)
 
 	// Wait a bit to ensure fill (if using limit) or just small delay
 	time.Sleep(2 * time.Second)
 
 	// 3. Place Sell Order (Close position)
 	// Note: In a real scenario, we should check if Buy was filled.
-	sellPrice := price * (1 - s.cfg.MaxSlippage)
+	sellPrice := price * (1 - cfg.MaxSlippage)
 	sellReq := &exchange.OrderRequest{
 		Symbol:     symbol,
 		Side:       "sell",
@@ -109,10 +152,93 @@ func (s *XPFarmingStrategy) executeFarming() {
 
 	sellRes, err := exc.PlaceOrder(sellReq)
 	if err != nil {
-		log.Printf("XP Farming: Sell failed: %v", err)
+		l.Error("sell failed", logger.Err(err))
+		s.notifier.Critical("Wash trade sell failed", err.Error(), map[string]any{
+			"venue": targetExchange, "symbol": symbol, "size": size, "buy_order": buyRes.OrderID,
+		})
 		return
 	}
-	log.Printf("XP Farming: Sell placed (Status: %s, ID: %s)", sellRes.Status, sellRes.OrderID)
+	l.Info("sell placed", "status", sellRes.Status, logger.KeyOrderID, sellRes.OrderID)
+	s.trackResting(targetExchange, symbol, sellRes)
+}
+
+func (s *XPFarmingStrategy) config() config.XPFarmingConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg
+}
+
+// UpdateConfig applies a reloaded config from the next run on.
+func (s *XPFarmingStrategy) UpdateConfig(cfg config.XPFarmingConfig) {
+	s.mu.Lock()
+	old := s.cfg
+	s.cfg = cfg
+	s.mu.Unlock()
+
+	if cfg.Enabled != old.Enabled {
+		if cfg.Enabled {
+			s.Resume()
+		} else {
+			s.Pause()
+		}
+	}
+}
+
+func (s *XPFarmingStrategy) trackResting(exchangeName, symbol string, res *exchange.OrderResponse) {
+	if res.Status != "open" || res.OrderID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resting = append(s.resting, restingOrder{exchange: exchangeName, symbol: symbol, orderID: res.OrderID})
+	if len(s.resting) > maxRestingOrders {
+		s.resting = s.resting[len(s.resting)-maxRestingOrders:]
+	}
+}
+
+func (s *XPFarmingStrategy) CancelRestingOrders() {
+	s.mu.Lock()
+	resting := s.resting
+	s.resting = nil
+	s.mu.Unlock()
+
+	for _, o := range resting {
+		exc, ok := s.venues.Get(o.exchange)
+		if !ok {
+			continue
+		}
+		// Orders that have since filled will fail to cancel; that's fine
+		if err := exc.CancelOrder(o.symbol, o.orderID); err != nil {
+			s.log.Warn("failed to cancel order", logger.KeyVenue, o.exchange, logger.KeyOrderID, o.orderID, logger.Err(err))
+			continue
+		}
+		s.log.Info("cancelled resting order", logger.KeyVenue, o.exchange, logger.KeyOrderID, o.orderID)
+	}
+}
+
+func (s *XPFarmingStrategy) Status() Status {
+	status := Status{
+		Name:          s.Name(),
+		Running:       s.running.Load(),
+		Paused:        s.paused.Load(),
+		ExecuteTrades: true, // wash trades are always executed
+	}
+	if last := s.lastRun.Load(); last != 0 {
+		status.LastRun = time.Unix(0, last)
+	}
+	return status
+}
+
+func (s *XPFarmingStrategy) Pause() {
+	s.paused.Store(true)
+	s.log.Info("strategy paused")
+}
+
+func (s *XPFarmingStrategy) Resume() {
+	s.paused.Store(false)
+	s.log.Info("strategy resumed")
 }
 
 func (s *XPFarmingStrategy) randomDuration(min, max time.Duration) time.Duration {
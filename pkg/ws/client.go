@@ -0,0 +1,310 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"arbitrage-bot/internal/logger"
+)
+
+var log = logger.For("ws")
+
+// ErrNotConnected is returned by Send while there is no connection.
+var ErrNotConnected = errors.New("websocket not connected")
+
+// State is the connection state of a Client.
+type State int
+
+const (
+	StateConnecting State = iota
+	StateConnected
+	StateDisconnected // waiting to reconnect
+	StateClosed       // Run returned
+)
+
+func (s State) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateClosed:
+		return "closed"
+	default:
+		return "connecting"
+	}
+}
+
+// Config describes one WebSocket endpoint and its protocol. Only URL and
+// OnMessage are required.
+type Config struct {
+	Name string // for logs, e.g. the venue
+	URL  string
+
+	// Build the messages that (un)subscribe a channel. Every subscribed
+	// channel is subscribed again after a reconnect.
+	SubscribeMsg   func(channel string) any
+	UnsubscribeMsg func(channel string) any
+
+	// Ping, if set, builds an application-level ping sent every
+	// PingInterval. Protocol-level pings are answered automatically.
+	Ping         func() any
+	PingInterval time.Duration
+
+	// The connection is dropped if nothing arrives for this long
+	// (default 60s).
+	ReadTimeout time.Duration
+
+	// Reconnect delay, doubling from MinBackoff up to MaxBackoff (defaults
+	// 1s and 30s) and reset once a connection is established.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnMessage is called with every data frame, from the read goroutine.
+	OnMessage func(data []byte)
+	// OnStateChange, if set, is called on every state transition.
+	OnStateChange func(State)
+}
+
+// Queued outgoing messages per connection
+const sendQueueSize = 64
+
+const writeTimeout = 10 * time.Second
+
+// Client is a WebSocket connection that reconnects with backoff and
+// resubscribes its channels. All writes go through a single goroutine, as
+// gorilla/websocket doesn't allow concurrent writers.
+type Client struct {
+	cfg Config
+	log *slog.Logger
+
+	mu            sync.Mutex
+	state         State
+	subscriptions map[string]bool
+	out           chan any // nil while disconnected
+}
+
+// NewClient creates a client; call Run to connect.
+func NewClient(cfg Config) *Client {
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = 60 * time.Second
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+	return &Client{
+		cfg:           cfg,
+		log:           log.With(logger.KeyVenue, cfg.Name),
+		subscriptions: make(map[string]bool),
+	}
+}
+
+// State returns the current connection state.
+func (c *Client) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Subscribe adds channel to the subscriptions, sending the subscribe
+// message now if connected and on every reconnect.
+func (c *Client) Subscribe(channel string) error {
+	c.mu.Lock()
+	c.subscriptions[channel] = true
+	c.mu.Unlock()
+
+	if c.cfg.SubscribeMsg == nil {
+		return nil
+	}
+	if err := c.Send(c.cfg.SubscribeMsg(channel)); err != nil && !errors.Is(err, ErrNotConnected) {
+		return err
+	}
+	return nil
+}
+
+// Unsubscribe removes channel from the subscriptions.
+func (c *Client) Unsubscribe(channel string) error {
+	c.mu.Lock()
+	delete(c.subscriptions, channel)
+	c.mu.Unlock()
+
+	if c.cfg.UnsubscribeMsg == nil {
+		return nil
+	}
+	if err := c.Send(c.cfg.UnsubscribeMsg(channel)); err != nil && !errors.Is(err, ErrNotConnected) {
+		return err
+	}
+	return nil
+}
+
+// Send queues msg (JSON-encoded, or sent as is if []byte) for the current
+// connection.
+func (c *Client) Send(msg any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.out == nil {
+		return ErrNotConnected
+	}
+	select {
+	case c.out <- msg:
+		return nil
+	default:
+		return errors.New("websocket send queue full")
+	}
+}
+
+// Run connects and keeps the connection up until ctx is cancelled.
+func (c *Client) Run(ctx context.Context) {
+	defer c.setState(StateClosed)
+
+	backoff := c.cfg.MinBackoff
+	for {
+		c.setState(StateConnecting)
+		connected, err := c.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = c.cfg.MinBackoff
+		}
+		c.setState(StateDisconnected)
+
+		// Jittered so many clients don't reconnect in lockstep
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		c.log.Warn("websocket disconnected, reconnecting", "url", c.cfg.URL, "in", wait, logger.Err(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		backoff = min(2*backoff, c.cfg.MaxBackoff)
+	}
+}
+
+// session runs one connection until it fails or ctx is cancelled. It
+// reports whether the connection was established.
+func (c *Client) session(ctx context.Context) (bool, error) {
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.DialContext(ctx, c.cfg.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// Resubscribe before anything else can be queued
+	c.mu.Lock()
+	out := make(chan any, sendQueueSize+len(c.subscriptions))
+	if c.cfg.SubscribeMsg != nil {
+		for channel := range c.subscriptions {
+			out <- c.cfg.SubscribeMsg(channel)
+		}
+	}
+	c.out = out
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.out = nil
+		c.mu.Unlock()
+	}()
+
+	c.log.Info("websocket connected", "url", c.cfg.URL)
+	c.setState(StateConnected)
+
+	sessCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- c.writeLoop(sessCtx, conn, out)
+	}()
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- c.readLoop(conn)
+	}()
+
+	select {
+	case <-ctx.Done():
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		return true, ctx.Err()
+	case err := <-writeErr:
+		return true, err
+	case err := <-readErr:
+		return true, err
+	}
+}
+
+// writeLoop is the only writer of conn.
+func (c *Client) writeLoop(ctx context.Context, conn *websocket.Conn, out <-chan any) error {
+	var ping <-chan time.Time
+	if c.cfg.Ping != nil && c.cfg.PingInterval > 0 {
+		ticker := time.NewTicker(c.cfg.PingInterval)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+
+	write := func(msg any) error {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if b, ok := msg.([]byte); ok {
+			return conn.WriteMessage(websocket.TextMessage, b)
+		}
+		return conn.WriteJSON(msg)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-out:
+			if err := write(msg); err != nil {
+				return err
+			}
+		case <-ping:
+			if err := write(c.cfg.Ping()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readLoop delivers messages until the connection fails or goes quiet for
+// longer than the read timeout.
+func (c *Client) readLoop(conn *websocket.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		conn.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout))
+		c.cfg.OnMessage(data)
+	}
+}
+
+func (c *Client) setState(state State) {
+	c.mu.Lock()
+	changed := c.state != state
+	c.state = state
+	c.mu.Unlock()
+
+	if changed && c.cfg.OnStateChange != nil {
+		c.cfg.OnStateChange(state)
+	}
+}
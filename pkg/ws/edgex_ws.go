@@ -4,21 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
-	"github.com/gorilla/websocket"
+	"arbitrage-bot/internal/logger"
 )
 
 // EdgeXWSClient handles WebSocket connection to EdgeX
 type EdgeXWSClient struct {
-	url         string
-	conn        *websocket.Conn
-	mu          sync.RWMutex
-	handlers    map[string]func(json.RawMessage)
-	stopCh      chan struct{}
-	reconnectCh chan struct{}
+	client *Client
+	log    *slog.Logger
+
+	// OnStateChange, if set before Connect, is called on every connection
+	// state transition.
+	OnStateChange func(State)
+
+	mu       sync.RWMutex
+	handlers map[string]func(json.RawMessage)
+	cancel   context.CancelFunc
 }
 
 type EdgeXWSMessage struct {
@@ -35,57 +39,99 @@ type EdgeXTickerContent struct {
 }
 
 type EdgeXTicker struct {
-	ContractId string `json:"contractId"`
-	LastPrice  string `json:"lastPrice"`
-	IndexPrice string `json:"indexPrice"`
-	MarkPrice  string `json:"markPrice"`
-	// Add more fields as needed
+	ContractId      string `json:"contractId"`
+	ContractName    string `json:"contractName"`
+	LastPrice       string `json:"lastPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	MarkPrice       string `json:"markPrice"`
+	OraclePrice     string `json:"oraclePrice"`
+	FundingRate     string `json:"fundingRate"`
+	FundingTime     string `json:"fundingTime"`     // ms
+	NextFundingTime string `json:"nextFundingTime"` // ms
+}
+
+// EdgeXDepthContent is the content of depth.<contractId>.<levels> events.
+// The first event after subscribing is a snapshot, the rest are changes.
+type EdgeXDepthContent struct {
+	DataType string       `json:"dataType"` // "Snapshot" or "Changed"
+	Channel  string       `json:"channel"`
+	Data     []EdgeXDepth `json:"data"`
+}
+
+type EdgeXDepth struct {
+	ContractId   string       `json:"contractId"`
+	StartVersion string       `json:"startVersion"`
+	EndVersion   string       `json:"endVersion"`
+	Bids         []EdgeXLevel `json:"bids"`
+	Asks         []EdgeXLevel `json:"asks"`
+}
+
+// EdgeXLevel is a price level; in changes, size 0 removes the level.
+type EdgeXLevel struct {
+	Price string `json:"price"`
+	Size  string `json:"size"`
 }
 
 func NewEdgeXWSClient(url string) *EdgeXWSClient {
-	return &EdgeXWSClient{
-		url:         url,
-		handlers:    make(map[string]func(json.RawMessage)),
-		stopCh:      make(chan struct{}),
-		reconnectCh: make(chan struct{}, 1),
+	c := &EdgeXWSClient{
+		log:      log.With(logger.KeyVenue, "edgex"),
+		handlers: make(map[string]func(json.RawMessage)),
 	}
+	c.client = NewClient(Config{
+		Name: "edgex",
+		URL:  url,
+		SubscribeMsg: func(channel string) any {
+			return EdgeXWSMessage{Type: "subscribe", Channel: channel}
+		},
+		UnsubscribeMsg: func(channel string) any {
+			return EdgeXWSMessage{Type: "unsubscribe", Channel: channel}
+		},
+		Ping: func() any {
+			return EdgeXWSMessage{Type: "ping", Time: fmt.Sprintf("%d", time.Now().UnixMilli())}
+		},
+		PingInterval: 30 * time.Second,
+		// The server pings every few seconds, so a quiet minute means the
+		// connection is dead
+		ReadTimeout: 60 * time.Second,
+		OnMessage:   c.handleMessage,
+		OnStateChange: func(state State) {
+			if c.OnStateChange != nil {
+				c.OnStateChange(state)
+			}
+		},
+	})
+	return c
 }
 
+// Connect starts the connection in the background. It reconnects and
+// resubscribes on failure until ctx is cancelled or Close is called.
 func (c *EdgeXWSClient) Connect(ctx context.Context) error {
-	dialer := websocket.DefaultDialer
-	dialer.HandshakeTimeout = 10 * time.Second
-
-	conn, _, err := dialer.DialContext(ctx, c.url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to connect to EdgeX WebSocket: %w", err)
-	}
+	ctx, cancel := context.WithCancel(ctx)
 
 	c.mu.Lock()
-	c.conn = conn
+	if c.cancel != nil {
+		c.mu.Unlock()
+		cancel()
+		return fmt.Errorf("websocket already connected")
+	}
+	c.cancel = cancel
 	c.mu.Unlock()
 
-	log.Printf("EdgeX WebSocket connected to %s", c.url)
-
-	// Start message handler
-	go c.handleMessages()
-
-	// Start ping/pong handler
-	go c.handlePingPong()
-
+	go c.client.Run(ctx)
 	return nil
 }
 
+// State returns the connection state.
+func (c *EdgeXWSClient) State() State {
+	return c.client.State()
+}
+
 func (c *EdgeXWSClient) Subscribe(channel string, handler func(json.RawMessage)) error {
 	c.mu.Lock()
 	c.handlers[channel] = handler
 	c.mu.Unlock()
 
-	msg := EdgeXWSMessage{
-		Type:    "subscribe",
-		Channel: channel,
-	}
-
-	return c.sendMessage(msg)
+	return c.client.Subscribe(channel)
 }
 
 func (c *EdgeXWSClient) Unsubscribe(channel string) error {
@@ -93,117 +139,55 @@ func (c *EdgeXWSClient) Unsubscribe(channel string) error {
 	delete(c.handlers, channel)
 	c.mu.Unlock()
 
-	msg := EdgeXWSMessage{
-		Type:    "unsubscribe",
-		Channel: channel,
-	}
-
-	return c.sendMessage(msg)
+	return c.client.Unsubscribe(channel)
 }
 
-func (c *EdgeXWSClient) sendMessage(msg interface{}) error {
-	c.mu.RLock()
-	conn := c.conn
-	c.mu.RUnlock()
-
-	if conn == nil {
-		return fmt.Errorf("websocket not connected")
+func (c *EdgeXWSClient) handleMessage(data []byte) {
+	var msg EdgeXWSMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.log.Warn("invalid websocket message", logger.Err(err))
+		return
 	}
 
-	return conn.WriteJSON(msg)
-}
+	// Handle different message types
+	switch msg.Type {
+	case "ping":
+		// Respond with pong
+		pong := EdgeXWSMessage{
+			Type: "pong",
+			Time: msg.Time,
+		}
+		if err := c.client.Send(pong); err != nil {
+			c.log.Warn("pong failed", logger.Err(err))
+		}
 
-func (c *EdgeXWSClient) handleMessages() {
-	for {
-		select {
-		case <-c.stopCh:
-			return
-		default:
-			c.mu.RLock()
-			conn := c.conn
-			c.mu.RUnlock()
-
-			if conn == nil {
-				time.Sleep(time.Second)
-				continue
-			}
+	case "pong":
+		// Ignore pong responses
 
-			var msg EdgeXWSMessage
-			err := conn.ReadJSON(&msg)
-			if err != nil {
-				log.Printf("EdgeX WS read error: %v", err)
-				// Trigger reconnect
-				select {
-				case c.reconnectCh <- struct{}{}:
-				default:
-				}
-				time.Sleep(time.Second)
-				continue
-			}
+	case "subscribed":
+		c.log.Info("subscribed", "channel", msg.Channel)
 
-			// Handle different message types
-			switch msg.Type {
-			case "ping":
-				// Respond with pong
-				pong := EdgeXWSMessage{
-					Type: "pong",
-					Time: msg.Time,
-				}
-				c.sendMessage(pong)
-
-			case "pong":
-				// Ignore pong responses
-
-			case "subscribed":
-				log.Printf("EdgeX WS subscribed to channel: %s", msg.Channel)
-
-			case "quote-event":
-				// Handle quote events
-				c.mu.RLock()
-				handler, ok := c.handlers[msg.Channel]
-				c.mu.RUnlock()
-
-				if ok && handler != nil {
-					handler(msg.Content)
-				}
-
-			case "error":
-				log.Printf("EdgeX WS error: %s", string(msg.Content))
-			}
-		}
-	}
-}
+	case "quote-event":
+		// Handle quote events
+		c.mu.RLock()
+		handler, ok := c.handlers[msg.Channel]
+		c.mu.RUnlock()
 
-func (c *EdgeXWSClient) handlePingPong() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-c.stopCh:
-			return
-		case <-ticker.C:
-			// Send ping
-			ping := EdgeXWSMessage{
-				Type: "ping",
-				Time: fmt.Sprintf("%d", time.Now().UnixMilli()),
-			}
-			if err := c.sendMessage(ping); err != nil {
-				log.Printf("EdgeX WS ping error: %v", err)
-			}
+		if ok && handler != nil {
+			handler(msg.Content)
 		}
+
+	case "error":
+		c.log.Error("websocket error message", "content", string(msg.Content))
 	}
 }
 
 func (c *EdgeXWSClient) Close() error {
-	close(c.stopCh)
-
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.conn != nil {
-		return c.conn.Close()
+	if c.cancel != nil {
+		c.cancel()
 	}
-
 	return nil
 }